@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/bemehow/terraform-provider-gandi/gandi/client"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Account identifies the Gandi account a provider is configured for
+type Account struct {
+	Id       int64
+	Handle   string
+	Fullname string
+	Profile  string
+}
+
+// String names the account in logs and errors
+func (a Account) String() string {
+	name := a.Handle
+	if name == "" {
+		name = fmt.Sprintf("#%d", a.Id)
+	}
+	if a.Profile != "" {
+		return fmt.Sprintf("%s (profile %s)", name, a.Profile)
+	}
+	return name
+}
+
+// getAccount reads the account owning the API key of the client
+func getAccount(c *client.Client) (Account, error) {
+	var account Account
+	var res map[string]interface{}
+	if err := c.Call("account.info", []interface{}{c.Key}, &res); err != nil {
+		return account, err
+	}
+
+	account.Id, _ = res["id"].(int64)
+	account.Handle, _ = res["handle"].(string)
+	account.Fullname, _ = res["fullname"].(string)
+
+	return account, nil
+}
+
+// withAccount names the configured account in the logs and errors of the
+// CRUD functions of a resource, so the failing provider alias is known when
+// several accounts are managed. It also binds the API calls made through
+// meta to the context of the CRUD function, so they are cancelled with it.
+func withAccount(name string, r *schema.Resource) *schema.Resource {
+	wrap := func(op string, f func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+		if f == nil {
+			return nil
+		}
+		return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+			account := meta.(*Meta).Account
+			log.Printf("[DEBUG] %s %s %s as account %s", op, name, d.Id(), account)
+
+			diags := f(ctx, d, meta.(*Meta).WithContext(ctx))
+			for i := range diags {
+				if diags[i].Severity == diag.Error {
+					diags[i].Summary = fmt.Sprintf("%s: %s (account %s)", name, diags[i].Summary, account)
+				}
+			}
+			return diags
+		}
+	}
+
+	r.CreateContext = wrap("Create", r.CreateContext)
+	r.ReadContext = wrap("Read", r.ReadContext)
+	r.UpdateContext = wrap("Update", r.UpdateContext)
+	r.DeleteContext = wrap("Delete", r.DeleteContext)
+
+	return r
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"time"
+)
+
+// commands can be run from the provider binary instead of serving the plugin,
+// e.g. terraform-provider-gandi export-zone -zone-id 123 -origin example.com
+var commands = map[string]func(args []string) error{
+	"export-zone":     exportZoneCommand,
+	"generate-config": generateConfigCommand,
+}
+
+// runCommand runs the named command and returns the process exit code
+func runCommand(name string, args []string) int {
+	command, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unknown command: %s\n", name)
+		return 1
+	}
+
+	if err := command(args); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 1
+	}
+	return 0
+}
+
+// commandFlags returns a flag set with the provider settings shared by all
+// commands, the key is then resolved like the provider does with LoadKey
+func commandFlags(name string, config *Config) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+
+	testing, _ := strconv.ParseBool(os.Getenv("GANDI_TESTING"))
+	flags.StringVar(&config.Key, "key", "", "Gandi XMLRPC API Key, defaults to GANDI_KEY")
+	flags.StringVar(&config.KeyFile, "key-file", "", "File holding the API Key, defaults to GANDI_KEY_FILE")
+	flags.StringVar(&config.Profile, "profile", "", "Account of the credentials file to use, defaults to GANDI_PROFILE")
+	flags.StringVar(&config.CredentialsFile, "credentials-file", "", "INI file of named accounts")
+	flags.BoolVar(&config.Testing, "testing", testing, "Use the Test Environment, defaults to GANDI_TESTING")
+	flags.StringVar(&config.MetricsFile, "metrics-file", os.Getenv("GANDI_METRICS_FILE"),
+		"JSON file receiving the API call metrics, defaults to GANDI_METRICS_FILE")
+	trace, _ := strconv.ParseBool(os.Getenv("GANDI_TRACE"))
+	flags.BoolVar(&config.Trace, "trace", trace, "Log every API call, defaults to GANDI_TRACE")
+	flags.StringVar(&config.TraceFile, "trace-file", os.Getenv("GANDI_TRACE_FILE"),
+		"File the API calls are appended to as JSON lines, defaults to GANDI_TRACE_FILE")
+	apiTimeout, _ := time.ParseDuration(defaultAPITimeout)
+	flags.DurationVar(&config.APITimeout, "api-timeout", apiTimeout, "How long a single API call may take, 0 for no limit")
+
+	return flags
+}
+
+// exportZoneCommand writes a zone version in BIND format to a file or stdout
+func exportZoneCommand(args []string) error {
+	var config Config
+	var zoneID, zoneVersion int64
+	var origin, output string
+
+	flags := commandFlags("export-zone", &config)
+	flags.Int64Var(&zoneID, "zone-id", 0, "ID of the zone to export")
+	flags.Int64Var(&zoneVersion, "version", 0, "Zone version to export, defaults to the active version")
+	flags.StringVar(&origin, "origin", "", "Domain name the record names are relative to")
+	flags.StringVar(&output, "output", "", "File to write, defaults to stdout")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if err := config.LoadKey(); err != nil {
+		return err
+	}
+	if zoneID == 0 || origin == "" {
+		return fmt.Errorf("-zone-id and -origin are required")
+	}
+
+	meta, err := config.Meta(context.Background())
+	if err != nil {
+		return err
+	}
+
+	content, _, err := ExportZoneFile(meta.WithContext(context.Background()), zoneID, zoneVersion, origin)
+	if err != nil {
+		return err
+	}
+
+	if output == "" {
+		_, err = fmt.Print(content)
+		return err
+	}
+	return ioutil.WriteFile(output, []byte(content), 0644)
+}
+
+// generateConfigCommand writes HCL and import commands for existing zones,
+// e.g. terraform-provider-gandi generate-config example.com example.org
+func generateConfigCommand(args []string) error {
+	var config Config
+	var output, importScript string
+
+	flags := commandFlags("generate-config", &config)
+	flags.StringVar(&output, "output", "gandi.tf", "File to write the configuration to")
+	flags.StringVar(&importScript, "import-script", "import.sh", "File to write the terraform import commands to")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if err := config.LoadKey(); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return fmt.Errorf("at least one zone name is required")
+	}
+
+	meta, err := config.Meta(context.Background())
+	if err != nil {
+		return err
+	}
+
+	configs, err := ListZoneConfigs(meta.WithContext(context.Background()), flags.Args())
+	if err != nil {
+		return err
+	}
+
+	hcl, imports := GenerateConfig(configs)
+	if err := ioutil.WriteFile(output, []byte(hcl), 0644); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(importScript, []byte("#!/bin/sh\nset -e\n\n"+imports), 0755)
+}
@@ -1,15 +1,160 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"fmt"
+	"io/ioutil"
 	"log"
+	"os"
+	"strings"
+	"time"
 
-	"github.com/prasmussen/gandi-api/client"
+	"github.com/bemehow/terraform-provider-gandi/gandi/client"
+	"github.com/mitchellh/go-homedir"
 )
 
+// DefaultCredentialsFile holds named accounts, one section per profile:
+//
+//	[default]
+//	key = ...
+const DefaultCredentialsFile = "~/.config/gandi/credentials"
+
+// DefaultProfile is the profile used when none is selected
+const DefaultProfile = "default"
+
 // Config contains DNSMadeEasy provider settings
 type Config struct {
-	Key     string
-	Testing bool
+	Key             string
+	KeyFile         string
+	Profile         string
+	CredentialsFile string
+	Testing         bool
+
+	// OperationTimeout bounds the wait for asynchronous operations
+	OperationTimeout time.Duration
+	// APITimeout bounds each XML-RPC call made by resources
+	APITimeout time.Duration
+	// MetricsFile receives the API call metrics as JSON when set
+	MetricsFile string
+	// Trace logs every API call, TraceFile also receives them as JSON lines
+	Trace     bool
+	TraceFile string
+}
+
+// LoadKey resolves the API key, the first source set wins:
+//
+//  1. key, key_file or profile from the provider configuration
+//  2. GANDI_KEY, GANDI_KEY_FILE or GANDI_PROFILE from the environment
+//  3. the default profile of the credentials file, if there is one
+//
+// The credentials file is credentials_file, GANDI_CREDENTIALS_FILE or
+// DefaultCredentialsFile.
+func (c *Config) LoadKey() error {
+	if c.CredentialsFile == "" {
+		c.CredentialsFile = os.Getenv("GANDI_CREDENTIALS_FILE")
+	}
+	if c.CredentialsFile == "" {
+		c.CredentialsFile = DefaultCredentialsFile
+	}
+
+	var err error
+	switch {
+	case c.Key != "":
+		log.Printf("[DEBUG] Using the Gandi API key from the provider configuration")
+	case c.KeyFile != "":
+		c.Key, err = readKeyFile(c.KeyFile)
+	case c.Profile != "":
+		c.Key, err = readProfileKey(c.CredentialsFile, c.Profile)
+	case os.Getenv("GANDI_KEY") != "":
+		log.Printf("[DEBUG] Using the Gandi API key from GANDI_KEY")
+		c.Key = os.Getenv("GANDI_KEY")
+	case os.Getenv("GANDI_KEY_FILE") != "":
+		c.KeyFile = os.Getenv("GANDI_KEY_FILE")
+		c.Key, err = readKeyFile(c.KeyFile)
+	case os.Getenv("GANDI_PROFILE") != "":
+		c.Profile = os.Getenv("GANDI_PROFILE")
+		c.Key, err = readProfileKey(c.CredentialsFile, c.Profile)
+	default:
+		path, _ := homedir.Expand(c.CredentialsFile)
+		if _, statErr := os.Stat(path); statErr == nil {
+			c.Profile = DefaultProfile
+			c.Key, err = readProfileKey(c.CredentialsFile, c.Profile)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if c.Key == "" {
+		return fmt.Errorf("No Gandi API key found: set key, key_file or profile, " +
+			"or GANDI_KEY, GANDI_KEY_FILE or GANDI_PROFILE")
+	}
+	return nil
+}
+
+// readKeyFile returns the API key stored in a file, e.g. a mounted secret
+func readKeyFile(path string) (string, error) {
+	log.Printf("[DEBUG] Reading the Gandi API key from: %s", path)
+
+	expanded, err := homedir.Expand(path)
+	if err != nil {
+		return "", fmt.Errorf("Cannot read key file %s: %v", path, err)
+	}
+	content, err := ioutil.ReadFile(expanded)
+	if err != nil {
+		return "", fmt.Errorf("Cannot read key file %s: %v", path, err)
+	}
+
+	key := strings.TrimSpace(string(content))
+	if key == "" {
+		return "", fmt.Errorf("Key file %s is empty", path)
+	}
+	return key, nil
+}
+
+// readProfileKey returns the key of a profile from an INI style credentials file
+func readProfileKey(path string, profile string) (string, error) {
+	log.Printf("[DEBUG] Reading the Gandi API key of profile %s from: %s", profile, path)
+
+	expanded, err := homedir.Expand(path)
+	if err != nil {
+		return "", fmt.Errorf("Cannot read credentials file %s: %v", path, err)
+	}
+	f, err := os.Open(expanded)
+	if err != nil {
+		return "", fmt.Errorf("Cannot read credentials file %s: %v", path, err)
+	}
+	defer f.Close()
+
+	var section string
+	found := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";"):
+			continue
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			if section == profile {
+				found = true
+			}
+		case section == profile:
+			parts := strings.SplitN(line, "=", 2)
+			if len(parts) == 2 && strings.TrimSpace(parts[0]) == "key" {
+				return strings.TrimSpace(parts[1]), nil
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("Cannot read credentials file %s: %v", path, err)
+	}
+
+	if !found {
+		return "", fmt.Errorf("Profile %s not found in %s", profile, path)
+	}
+	return "", fmt.Errorf("Profile %s in %s has no key", profile, path)
 }
 
 // Env gets appropriate system type
@@ -20,11 +165,64 @@ func (c *Config) Env() client.SystemType {
 	return client.Production
 }
 
-// Client returns a new client for accessing Gandi API via meta passed to CRUD
+// Meta is the configured provider passed to CRUD functions
+type Meta struct {
+	Account Account
+	System  client.SystemType
+	Client  *client.Client
+	Cache   *ZoneCache
+
+	OperationTimeout time.Duration
+	APITimeout       time.Duration
+}
+
+// WithContext returns a copy of the Meta whose API calls are cancelled with
+// ctx and each last at most APITimeout, the cache is shared
+func (m *Meta) WithContext(ctx context.Context) *Meta {
+	bound := *m
+	bound.Client = bindClient(ctx, m.Client, m.APITimeout)
+	return &bound
+}
+
+// Meta returns the clients for the account owning the API key, which is
+// looked up within ctx and APITimeout
+func (c *Config) Meta(ctx context.Context) (*Meta, error) {
+	if err := apiTrace.Configure(c.Trace, c.TraceFile); err != nil {
+		return nil, err
+	}
+	gandiClient := c.Client()
+
+	account, err := getAccount(bindClient(ctx, gandiClient, c.APITimeout))
+	if err != nil {
+		if c.Profile != "" {
+			return nil, wrapAPIError(err, "Cannot get account info of profile %s", c.Profile)
+		}
+		return nil, wrapAPIError(err, "Cannot get account info")
+	}
+	account.Profile = c.Profile
+	log.Printf("[INFO] Gandi Client configured for account: %s", account)
+
+	return &Meta{
+		Account:          account,
+		System:           c.Env(),
+		Client:           gandiClient,
+		Cache:            NewZoneCache(),
+		OperationTimeout: c.OperationTimeout,
+		APITimeout:       c.APITimeout,
+	}, nil
+}
+
+// Client returns a new client for accessing Gandi API
 func (c *Config) Client() *client.Client {
+	// The key must never reach the logs, including XML-RPC dumps
+	logSecrets.Register(c.Key)
+	installTransport()
+	if c.MetricsFile != "" {
+		apiMetrics.SetFile(c.MetricsFile)
+	}
 
 	gandiClient := client.New(c.Key, c.Env())
-	log.Printf("[INFO] Gandi Client configured for URL: %s with Key: %s", gandiClient.Url, c.Key)
+	log.Printf("[INFO] Gandi Client configured for URL: %s", gandiClient.Url)
 
 	return gandiClient
 }
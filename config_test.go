@@ -0,0 +1,126 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testCredentials = `
+# accounts managed by terraform
+[default]
+key = default-key
+
+[reseller]
+key = reseller-key
+
+[empty]
+`
+
+// testKeyEnv clears the environment LoadKey reads and writes test files
+func testKeyEnv(t *testing.T) (string, string) {
+	for _, env := range []string{"GANDI_KEY", "GANDI_KEY_FILE", "GANDI_PROFILE", "GANDI_CREDENTIALS_FILE"} {
+		t.Setenv(env, "")
+	}
+
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "key")
+	credentialsFile := filepath.Join(dir, "credentials")
+	if err := ioutil.WriteFile(keyFile, []byte("file-key\n"), 0600); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := ioutil.WriteFile(credentialsFile, []byte(testCredentials), 0600); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	return keyFile, credentialsFile
+}
+
+func TestConfigLoadKey(t *testing.T) {
+	keyFile, credentialsFile := testKeyEnv(t)
+
+	cases := []struct {
+		name   string
+		config Config
+		env    map[string]string
+		key    string
+	}{
+		{
+			name:   "key wins over the environment",
+			config: Config{Key: "config-key"},
+			env:    map[string]string{"GANDI_KEY": "env-key"},
+			key:    "config-key",
+		},
+		{
+			name:   "key_file wins over the environment",
+			config: Config{KeyFile: keyFile},
+			env:    map[string]string{"GANDI_KEY": "env-key"},
+			key:    "file-key",
+		},
+		{
+			name:   "profile wins over the environment",
+			config: Config{Profile: "reseller", CredentialsFile: credentialsFile},
+			env:    map[string]string{"GANDI_KEY": "env-key"},
+			key:    "reseller-key",
+		},
+		{
+			name:   "GANDI_KEY wins over GANDI_KEY_FILE",
+			config: Config{CredentialsFile: credentialsFile},
+			env:    map[string]string{"GANDI_KEY": "env-key", "GANDI_KEY_FILE": keyFile},
+			key:    "env-key",
+		},
+		{
+			name:   "GANDI_KEY_FILE wins over GANDI_PROFILE",
+			config: Config{CredentialsFile: credentialsFile},
+			env:    map[string]string{"GANDI_KEY_FILE": keyFile, "GANDI_PROFILE": "reseller"},
+			key:    "file-key",
+		},
+		{
+			name:   "GANDI_PROFILE with GANDI_CREDENTIALS_FILE",
+			config: Config{},
+			env:    map[string]string{"GANDI_PROFILE": "reseller", "GANDI_CREDENTIALS_FILE": credentialsFile},
+			key:    "reseller-key",
+		},
+		{
+			name:   "default profile as a last resort",
+			config: Config{CredentialsFile: credentialsFile},
+			key:    "default-key",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			for k, v := range c.env {
+				t.Setenv(k, v)
+			}
+
+			config := c.config
+			if err := config.LoadKey(); err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			if config.Key != c.key {
+				t.Fatalf("expected key %q, got %q", c.key, config.Key)
+			}
+		})
+	}
+}
+
+func TestConfigLoadKeyErrors(t *testing.T) {
+	_, credentialsFile := testKeyEnv(t)
+	missing := filepath.Join(t.TempDir(), "missing")
+
+	cases := map[string]Config{
+		"No Gandi API key found":   Config{CredentialsFile: missing},
+		"Cannot read key file":     Config{KeyFile: missing},
+		"Profile nope not found":   Config{Profile: "nope", CredentialsFile: credentialsFile},
+		"Profile empty in":         Config{Profile: "empty", CredentialsFile: credentialsFile},
+		"Cannot read credentials ": Config{Profile: "default", CredentialsFile: missing},
+	}
+
+	for message, config := range cases {
+		err := config.LoadKey()
+		if err == nil || !strings.Contains(err.Error(), message) {
+			t.Fatalf("expected error containing %q, got: %v", message, err)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/bemehow/terraform-provider-gandi/gandi/client"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceAccount() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: ReadAccount,
+
+		Schema: map[string]*schema.Schema{
+			"handle": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"fullname": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"profile": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"testing": &schema.Schema{
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// ReadAccount exposes the account the provider is configured for
+func ReadAccount(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	m := meta.(*Meta)
+
+	d.SetId(strconv.FormatInt(m.Account.Id, 10))
+	d.Set("handle", m.Account.Handle)
+	d.Set("fullname", m.Account.Fullname)
+	d.Set("profile", m.Account.Profile)
+	d.Set("testing", m.System == client.Testing)
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccGandiAccount(t *testing.T) {
+	testingEnv, _ := strconv.ParseBool(os.Getenv("GANDI_TESTING"))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testGandiAccountConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"data.gandi_account.test", "testing", strconv.FormatBool(testingEnv)),
+					resource.TestCheckResourceAttr(
+						"data.gandi_account.test", "profile", ""),
+				),
+			},
+		},
+	})
+}
+
+const testGandiAccountConfig = `
+data "gandi_account" "test" {
+}`
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceDomain() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: ReadDomain,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"status": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"expires": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"autorenew": &schema.Schema{
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"nameservers": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"zone_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// ReadDomain looks up a domain registered in the account by name, its ID
+// is the domain ID expected by gandi_zone
+func ReadDomain(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	name := d.Get("name").(string)
+	log.Printf("[DEBUG] Reading domain: %v", name)
+
+	info, err := getDomainClient(meta).Info(name)
+	if err != nil {
+		return diag.FromErr(wrapAPIError(err, "Cannot read domain %s", name))
+	}
+
+	d.SetId(strconv.FormatInt(info.Id, 10))
+	d.Set("status", info.Status)
+	d.Set("expires", info.DateRegistryEnd.Format(time.RFC3339))
+	d.Set("autorenew", info.Autorenew != nil && info.Autorenew.Active)
+	d.Set("nameservers", info.Nameservers)
+	if info.ZoneId != 0 {
+		d.Set("zone_id", strconv.FormatInt(info.ZoneId, 10))
+	} else {
+		d.Set("zone_id", "")
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	// availabilityPollInterval is the delay between two domain.available calls
+	availabilityPollInterval = 2 * time.Second
+	// domainAvailable is the status of a domain that can be registered
+	domainAvailable = "available"
+	// domainAvailabilityPending is the status while registries are queried
+	domainAvailabilityPending = "pending"
+)
+
+func dataSourceDomainAvailability() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: ReadDomainAvailability,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"available": &schema.Schema{
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"status": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Status returned by Gandi, e.g. available, unavailable or available_reserved.",
+			},
+		},
+	}
+}
+
+// checkDomainAvailability asks Gandi whether the domain can be registered,
+// the check is asynchronous and polled until the registry answered
+func checkDomainAvailability(ctx context.Context, meta interface{}, name string) (string, error) {
+	c := meta.(*Meta).Client
+	deadline := time.Now().Add(meta.(*Meta).OperationTimeout)
+
+	for {
+		var res map[string]interface{}
+		if err := c.Call("domain.available", []interface{}{c.Key, []string{name}}, &res); err != nil {
+			return "", wrapAPIError(err, "Cannot check availability of %s", name)
+		}
+
+		status, _ := res[name].(string)
+		if status != domainAvailabilityPending {
+			return status, nil
+		}
+		if time.Now().After(deadline) {
+			return status, fmt.Errorf("Availability of %s still pending after %s", name, meta.(*Meta).OperationTimeout)
+		}
+
+		log.Printf("[DEBUG] Availability of %s pending", name)
+		select {
+		case <-ctx.Done():
+			return status, fmt.Errorf("Stopped checking availability of %s: %v", name, ctx.Err())
+		case <-time.After(availabilityPollInterval):
+		}
+	}
+}
+
+// ReadDomainAvailability checks whether a domain can be registered
+func ReadDomainAvailability(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	name := d.Get("name").(string)
+	log.Printf("[DEBUG] Checking availability of domain: %v", name)
+
+	status, err := checkDomainAvailability(ctx, meta, name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(name)
+	d.Set("status", status)
+	d.Set("available", status == domainAvailable)
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccGandiDomainAvailability(t *testing.T) {
+	domainName := os.Getenv("GANDI_DOMAIN")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckDomain(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: fmt.Sprintf(testGandiDomainAvailabilityConfig, domainName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"data.gandi_domain_availability.test", "available", "false"),
+					resource.TestCheckResourceAttr(
+						"data.gandi_domain_availability.test", "status", "unavailable"),
+				),
+			},
+		},
+	})
+}
+
+const testGandiDomainAvailabilityConfig = `
+data "gandi_domain_availability" "test" {
+  name = "%s"
+}`
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccGandiDomainData(t *testing.T) {
+	domainName := os.Getenv("GANDI_DOMAIN")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckDomain(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: fmt.Sprintf(testGandiDomainDataConfig, domainName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"data.gandi_domain.test", "name", domainName),
+					resource.TestCheckResourceAttr(
+						"gandi_zone.test", "name", "testing_domain_zone"),
+				),
+			},
+		},
+	})
+}
+
+const testGandiDomainDataConfig = `
+data "gandi_domain" "test" {
+  name = "%s"
+}
+
+resource "gandi_zone" "test" {
+  name = "testing_domain_zone"
+  domain_id = "${data.gandi_domain.test.id}"
+}`
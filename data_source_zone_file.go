@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceZoneFile() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: ReadZoneFile,
+
+		Schema: map[string]*schema.Schema{
+			"zone_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"version": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Zone version to export, defaults to the active version.",
+			},
+			"origin": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Domain name the record names are relative to.",
+			},
+			"content": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// ExportZoneFile renders a zone version as a master file, the active version
+// is used when zoneVersion is 0
+func ExportZoneFile(meta interface{}, zoneID int64, zoneVersion int64, origin string) (string, int64, error) {
+	var err error
+	if zoneVersion == 0 {
+		_, zoneVersion, err = getActiveZoneVersion(meta, zoneID)
+		if err != nil {
+			return "", 0, err
+		}
+	}
+
+	log.Printf("[DEBUG] Exporting zone: %v version: %v", zoneID, zoneVersion)
+	records, err := listRecords(meta, zoneID, zoneVersion)
+	if err != nil {
+		return "", 0, wrapAPIError(err, "Cannot read records from zone: %v version: %v", zoneID, zoneVersion)
+	}
+
+	return RenderZoneFile(origin, zoneVersion, records), zoneVersion, nil
+}
+
+// ReadZoneFile renders the records of a zone version in BIND format
+func ReadZoneFile(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zoneID, err := strconv.ParseInt(d.Get("zone_id").(string), 10, 64)
+	if err != nil {
+		return diag.Errorf("Invalid zone_id: %v", err)
+	}
+
+	var zoneVersion int64
+	if v := d.Get("version").(string); v != "" {
+		zoneVersion, err = strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return diag.Errorf("Invalid version: %v", err)
+		}
+	}
+
+	content, zoneVersion, err := ExportZoneFile(meta, zoneID, zoneVersion, d.Get("origin").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%d_%d", zoneID, zoneVersion))
+	d.Set("content", content)
+
+	return nil
+}
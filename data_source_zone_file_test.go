@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccGandiZoneFile(t *testing.T) {
+	zoneID := os.Getenv("GANDI_ZONE_ID")
+	zoneVersion := os.Getenv("GANDI_ZONE_VERSION")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckRecord(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: fmt.Sprintf(testGandiZoneFileConfig, zoneID, zoneVersion),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"data.gandi_zone_file.test", "id", zoneID+"_"+zoneVersion),
+					resource.TestCheckResourceAttr(
+						"data.gandi_zone_file.test", "origin", "example.com"),
+				),
+			},
+		},
+	})
+}
+
+const testGandiZoneFileConfig = `
+data "gandi_zone_file" "test" {
+  zone_id = "%s"
+  version = "%s"
+  origin = "example.com"
+}`
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+
+	"github.com/bemehow/terraform-provider-gandi/gandi/domain/zone/record"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceZoneVersionDiff() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: ReadZoneVersionDiff,
+
+		Schema: map[string]*schema.Schema{
+			"zone_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"from_version": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"to_version": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"added": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     recordSetSchema(),
+			},
+			"removed": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     recordSetSchema(),
+			},
+			"changed": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"type": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"old_ttl": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"new_ttl": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"old_values": &schema.Schema{
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"new_values": &schema.Schema{
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func recordSetSchema() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"type": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"ttl": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"values": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+// RecordSet groups the records of a zone version sharing a name and type
+type RecordSet struct {
+	Name   string
+	Type   string
+	Ttl    int64
+	Values []string
+}
+
+// RecordSetChange holds both sides of a record set present in two versions
+type RecordSetChange struct {
+	Old RecordSet
+	New RecordSet
+}
+
+// ZoneVersionDiff lists the record sets that differ between two zone versions
+type ZoneVersionDiff struct {
+	Added   []RecordSet
+	Removed []RecordSet
+	Changed []RecordSetChange
+}
+
+type recordSetKey struct {
+	Name string
+	Type string
+}
+
+// groupRecords builds the record sets of a zone version keyed by name and type
+func groupRecords(records []*record.RecordInfo) map[recordSetKey]*RecordSet {
+	sets := make(map[recordSetKey]*RecordSet)
+	for _, r := range records {
+		key := recordSetKey{Name: r.Name, Type: r.Type}
+		set, ok := sets[key]
+		if !ok {
+			set = &RecordSet{Name: r.Name, Type: r.Type, Ttl: r.Ttl}
+			sets[key] = set
+		}
+		// Resolvers use the lowest TTL when the records of a set disagree
+		if r.Ttl < set.Ttl {
+			set.Ttl = r.Ttl
+		}
+		set.Values = append(set.Values, unquoteRecordValue(r.Value))
+	}
+
+	for _, set := range sets {
+		sort.Strings(set.Values)
+	}
+
+	return sets
+}
+
+func sortedRecordSetKeys(sets ...map[recordSetKey]*RecordSet) []recordSetKey {
+	seen := make(map[recordSetKey]bool)
+	var keys []recordSetKey
+	for _, s := range sets {
+		for key := range s {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Name != keys[j].Name {
+			return keys[i].Name < keys[j].Name
+		}
+		return keys[i].Type < keys[j].Type
+	})
+
+	return keys
+}
+
+func (s *RecordSet) equal(other *RecordSet) bool {
+	if s.Ttl != other.Ttl || len(s.Values) != len(other.Values) {
+		return false
+	}
+	for i := range s.Values {
+		if s.Values[i] != other.Values[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// DiffRecords compares the records of two zone versions by name and type
+func DiffRecords(from []*record.RecordInfo, to []*record.RecordInfo) ZoneVersionDiff {
+	var diff ZoneVersionDiff
+
+	fromSets := groupRecords(from)
+	toSets := groupRecords(to)
+
+	for _, key := range sortedRecordSetKeys(fromSets, toSets) {
+		oldSet, inFrom := fromSets[key]
+		newSet, inTo := toSets[key]
+
+		switch {
+		case !inFrom:
+			diff.Added = append(diff.Added, *newSet)
+		case !inTo:
+			diff.Removed = append(diff.Removed, *oldSet)
+		case !oldSet.equal(newSet):
+			diff.Changed = append(diff.Changed, RecordSetChange{Old: *oldSet, New: *newSet})
+		}
+	}
+
+	return diff
+}
+
+func flattenRecordSets(sets []RecordSet) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(sets))
+	for _, s := range sets {
+		result = append(result, map[string]interface{}{
+			"name":   s.Name,
+			"type":   s.Type,
+			"ttl":    strconv.FormatInt(s.Ttl, 10),
+			"values": s.Values,
+		})
+	}
+	return result
+}
+
+func flattenRecordSetChanges(changes []RecordSetChange) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(changes))
+	for _, c := range changes {
+		result = append(result, map[string]interface{}{
+			"name":       c.New.Name,
+			"type":       c.New.Type,
+			"old_ttl":    strconv.FormatInt(c.Old.Ttl, 10),
+			"new_ttl":    strconv.FormatInt(c.New.Ttl, 10),
+			"old_values": c.Old.Values,
+			"new_values": c.New.Values,
+		})
+	}
+	return result
+}
+
+// ReadZoneVersionDiff lists the records of both versions and compares them
+func ReadZoneVersionDiff(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zoneID, err := strconv.ParseInt(d.Get("zone_id").(string), 10, 64)
+	if err != nil {
+		return diag.Errorf("Invalid zone_id: %v", err)
+	}
+	fromVersion, err := strconv.ParseInt(d.Get("from_version").(string), 10, 64)
+	if err != nil {
+		return diag.Errorf("Invalid from_version: %v", err)
+	}
+	toVersion, err := strconv.ParseInt(d.Get("to_version").(string), 10, 64)
+	if err != nil {
+		return diag.Errorf("Invalid to_version: %v", err)
+	}
+
+	log.Printf("[DEBUG] Comparing zone %v version %v with version %v", zoneID, fromVersion, toVersion)
+
+	fromRecords, err := listRecords(meta, zoneID, fromVersion)
+	if err != nil {
+		return diag.FromErr(wrapAPIError(err, "Cannot read records from zone: %v version: %v", zoneID, fromVersion))
+	}
+	toRecords, err := listRecords(meta, zoneID, toVersion)
+	if err != nil {
+		return diag.FromErr(wrapAPIError(err, "Cannot read records from zone: %v version: %v", zoneID, toVersion))
+	}
+
+	diff := DiffRecords(fromRecords, toRecords)
+	log.Printf("[DEBUG] Zone version diff: %d added, %d removed, %d changed",
+		len(diff.Added), len(diff.Removed), len(diff.Changed))
+
+	d.SetId(fmt.Sprintf("%d_%d_%d", zoneID, fromVersion, toVersion))
+	d.Set("added", flattenRecordSets(diff.Added))
+	d.Set("removed", flattenRecordSets(diff.Removed))
+	d.Set("changed", flattenRecordSetChanges(diff.Changed))
+
+	return nil
+}
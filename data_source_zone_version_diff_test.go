@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/bemehow/terraform-provider-gandi/gandi/domain/zone/record"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestDiffRecords(t *testing.T) {
+	from := []*record.RecordInfo{
+		{Id: 1, Name: "www", Type: "A", Value: "1.1.1.1", Ttl: 3600},
+		{Id: 2, Name: "www", Type: "A", Value: "2.2.2.2", Ttl: 3600},
+		{Id: 3, Name: "mail", Type: "MX", Value: "10 mx.example.com.", Ttl: 3600},
+		{Id: 4, Name: "old", Type: "CNAME", Value: "www", Ttl: 3600},
+		{Id: 5, Name: "txt", Type: "TXT", Value: `"foo"`, Ttl: 300},
+	}
+	to := []*record.RecordInfo{
+		{Id: 11, Name: "www", Type: "A", Value: "2.2.2.2", Ttl: 3600},
+		{Id: 12, Name: "www", Type: "A", Value: "3.3.3.3", Ttl: 3600},
+		{Id: 13, Name: "mail", Type: "MX", Value: "10 mx.example.com.", Ttl: 3600},
+		{Id: 15, Name: "txt", Type: "TXT", Value: `"foo"`, Ttl: 600},
+		{Id: 16, Name: "new", Type: "AAAA", Value: "fe80::1", Ttl: 3600},
+	}
+
+	diff := DiffRecords(from, to)
+
+	expected := ZoneVersionDiff{
+		Added: []RecordSet{
+			{Name: "new", Type: "AAAA", Ttl: 3600, Values: []string{"fe80::1"}},
+		},
+		Removed: []RecordSet{
+			{Name: "old", Type: "CNAME", Ttl: 3600, Values: []string{"www"}},
+		},
+		Changed: []RecordSetChange{
+			{
+				Old: RecordSet{Name: "txt", Type: "TXT", Ttl: 300, Values: []string{"foo"}},
+				New: RecordSet{Name: "txt", Type: "TXT", Ttl: 600, Values: []string{"foo"}},
+			},
+			{
+				Old: RecordSet{Name: "www", Type: "A", Ttl: 3600, Values: []string{"1.1.1.1", "2.2.2.2"}},
+				New: RecordSet{Name: "www", Type: "A", Ttl: 3600, Values: []string{"2.2.2.2", "3.3.3.3"}},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(diff, expected) {
+		t.Fatalf("unexpected diff:\n got: %+v\nwant: %+v", diff, expected)
+	}
+}
+
+func TestDiffRecordsIdentical(t *testing.T) {
+	records := []*record.RecordInfo{
+		{Id: 1, Name: "www", Type: "A", Value: "1.1.1.1", Ttl: 3600},
+	}
+
+	diff := DiffRecords(records, records)
+	if len(diff.Added) != 0 || len(diff.Removed) != 0 || len(diff.Changed) != 0 {
+		t.Fatalf("expected no differences, got: %+v", diff)
+	}
+}
+
+func TestAccGandiZoneVersionDiff(t *testing.T) {
+	zoneID := os.Getenv("GANDI_ZONE_ID")
+	zoneVersion := os.Getenv("GANDI_ZONE_VERSION")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckRecord(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: fmt.Sprintf(testGandiZoneVersionDiffConfig, zoneID, zoneVersion, zoneVersion),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"data.gandi_zone_version_diff.test", "added.#", "0"),
+					resource.TestCheckResourceAttr(
+						"data.gandi_zone_version_diff.test", "removed.#", "0"),
+					resource.TestCheckResourceAttr(
+						"data.gandi_zone_version_diff.test", "changed.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+const testGandiZoneVersionDiffConfig = `
+data "gandi_zone_version_diff" "test" {
+  zone_id = "%s"
+  from_version = "%s"
+  to_version = "%s"
+}`
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/kolo/xmlrpc"
+)
+
+// FaultKind classifies the faults returned by the Gandi API
+type FaultKind int
+
+const (
+	FaultUnknown FaultKind = iota
+	FaultNotFound
+	FaultPermissionDenied
+	FaultInvalidParameter
+	FaultRateLimited
+	FaultObjectLocked
+)
+
+func (k FaultKind) String() string {
+	switch k {
+	case FaultNotFound:
+		return "not found"
+	case FaultPermissionDenied:
+		return "permission denied"
+	case FaultInvalidParameter:
+		return "invalid parameter"
+	case FaultRateLimited:
+		return "rate limited"
+	case FaultObjectLocked:
+		return "object locked"
+	}
+	return "unknown"
+}
+
+var (
+	// Gandi fault strings read e.g. "Error on object : OBJECT_DOMAIN
+	// (CAUSE_NOTFOUND) [Domain 'example.com' doesn't exist.]"
+	faultCausePattern  = regexp.MustCompile(`\bCAUSE_([A-Z_]+)`)
+	faultObjectPattern = regexp.MustCompile(`\bOBJECT_([A-Z_]+)`)
+)
+
+// faultCodes maps the cause part of Gandi fault codes, their last three
+// digits, to their kind
+var faultCodes = map[int]FaultKind{
+	42:  FaultNotFound,
+	150: FaultPermissionDenied,
+}
+
+// faultCauses maps the CAUSE_ tokens of Gandi faults to their kind, for the
+// codes missing from faultCodes
+var faultCauses = map[string]FaultKind{
+	"NOTFOUND":      FaultNotFound,
+	"NORIGHT":       FaultPermissionDenied,
+	"ACCESSDENIED":  FaultPermissionDenied,
+	"FORBIDDEN":     FaultPermissionDenied,
+	"BADPARAMETER":  FaultInvalidParameter,
+	"BADREQUEST":    FaultInvalidParameter,
+	"DATAVALIDATE":  FaultInvalidParameter,
+	"TOOMANYREQ":    FaultRateLimited,
+	"RATELIMIT":     FaultRateLimited,
+	"LOCKED":        FaultObjectLocked,
+	"PENDING":       FaultObjectLocked,
+	"OPERATIONLOCK": FaultObjectLocked,
+}
+
+// GandiFault is a failed API call the provider can act upon
+type GandiFault struct {
+	Kind    FaultKind
+	Code    int64
+	Object  string
+	Cause   string
+	Message string
+	// Context says what the provider was doing when the call failed
+	Context string
+}
+
+// Error returns the fault in its context, followed by a hint
+func (f *GandiFault) Error() string {
+	msg := f.Message
+	if f.Context != "" {
+		msg = f.Context + ": " + msg
+	}
+	if hint := f.Hint(); hint != "" {
+		msg += " (" + hint + ")"
+	}
+	return msg
+}
+
+// Hint suggests how to solve the fault
+func (f *GandiFault) Hint() string {
+	switch f.Kind {
+	case FaultNotFound:
+		return "it may have been deleted outside Terraform"
+	case FaultPermissionDenied:
+		switch f.Object {
+		case "ZONE", "VERSION", "RECORD":
+			return "API key lacks DNS permission"
+		case "":
+			return "API key lacks permission for this call"
+		}
+		return "API key lacks permission on " + strings.ToLower(f.Object) + " objects"
+	case FaultInvalidParameter:
+		return "check the arguments of the resource"
+	case FaultRateLimited:
+		return "Gandi API rate limit reached, retry later or lower -parallelism"
+	case FaultObjectLocked:
+		return "another operation is pending on the object, retry once it completed"
+	}
+	return ""
+}
+
+// newNotFound returns a not found fault raised by the provider itself, e.g.
+// for an ID missing from a listing
+func newNotFound(format string, args ...interface{}) *GandiFault {
+	return &GandiFault{Kind: FaultNotFound, Message: fmt.Sprintf(format, args...)}
+}
+
+// parseGandiFault classifies the XML-RPC fault in err by its code, or by
+// the CAUSE_ token of its string for unknown codes. ok is false when err
+// holds no fault, e.g. a network error.
+func parseGandiFault(err error) (*GandiFault, bool) {
+	var gf *GandiFault
+	if errors.As(err, &gf) {
+		c := *gf
+		return &c, true
+	}
+
+	var fault xmlrpc.FaultError
+	if !errors.As(err, &fault) {
+		return nil, false
+	}
+
+	// the fault alone, err also names the URL when it comes from the transport
+	f := &GandiFault{Code: int64(fault.Code), Message: fault.Error()}
+	if m := faultObjectPattern.FindStringSubmatch(fault.String); m != nil {
+		f.Object = m[1]
+	}
+	if m := faultCausePattern.FindStringSubmatch(fault.String); m != nil {
+		f.Cause = m[1]
+	}
+
+	kind, ok := faultCodes[fault.Code%1000]
+	if !ok {
+		kind = faultCauses[f.Cause]
+	}
+	f.Kind = kind
+	return f, true
+}
+
+// newRateLimited is returned by gandiTransport when Gandi answers with
+// HTTP 429 Too Many Requests
+func newRateLimited(status string) *GandiFault {
+	return &GandiFault{Kind: FaultRateLimited, Message: "Gandi API answered " + status}
+}
+
+// wrapAPIError says what failed, keeping the fault so callers can branch
+// on its kind
+func wrapAPIError(err error, format string, args ...interface{}) error {
+	context := fmt.Sprintf(format, args...)
+	f, ok := parseGandiFault(err)
+	if !ok {
+		return fmt.Errorf("%s: %v", context, err)
+	}
+
+	if f.Context != "" {
+		context += ": " + f.Context
+	}
+	f.Context = context
+	return f
+}
+
+// faultKind returns the kind of a Gandi fault, FaultUnknown for any other
+// error
+func faultKind(err error) FaultKind {
+	if f, ok := parseGandiFault(err); ok {
+		return f.Kind
+	}
+	return FaultUnknown
+}
+
+// isNotFound reports whether err is a Gandi not found fault
+func isNotFound(err error) bool {
+	return faultKind(err) == FaultNotFound
+}
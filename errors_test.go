@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/kolo/xmlrpc"
+)
+
+func TestParseGandiFault(t *testing.T) {
+	cases := []struct {
+		fault  xmlrpc.FaultError
+		kind   FaultKind
+		object string
+	}{
+		{xmlrpc.FaultError{Code: 510042, String: "Error on object : OBJECT_DOMAIN (CAUSE_NOTFOUND) [Domain 'example.com' doesn't exist.]"},
+			FaultNotFound, "DOMAIN"},
+		{xmlrpc.FaultError{Code: 510150, String: "Error on object : OBJECT_ZONE (CAUSE_NORIGHT) [Access denied]"},
+			FaultPermissionDenied, "ZONE"},
+		// the code wins over the cause
+		{xmlrpc.FaultError{Code: 510042, String: "Error on object : OBJECT_RECORD (CAUSE_BADPARAMETER) [Unknown record]"},
+			FaultNotFound, "RECORD"},
+		// unknown codes fall back to the cause
+		{xmlrpc.FaultError{Code: 505002, String: "Error on object : OBJECT_RECORD (CAUSE_BADPARAMETER) [Invalid value]"},
+			FaultInvalidParameter, "RECORD"},
+		{xmlrpc.FaultError{Code: 581001, String: "Error on object : OBJECT_DOMAIN (CAUSE_PENDING) [An operation is already running]"},
+			FaultObjectLocked, "DOMAIN"},
+		{xmlrpc.FaultError{Code: 510000, String: "Error on object : OBJECT_UNKNOWN (CAUSE_UNKNOWN) [Internal error 429]"},
+			FaultUnknown, "UNKNOWN"},
+	}
+
+	for _, c := range cases {
+		f, ok := parseGandiFault(fmt.Errorf("Cannot read: %w", c.fault))
+		if !ok {
+			t.Fatalf("expected a fault: %s", c.fault)
+		}
+		if f.Kind != c.kind || f.Code != int64(c.fault.Code) || f.Object != c.object {
+			t.Fatalf("%s: expected %s %d %s, got %s %d %s", c.fault, c.kind, c.fault.Code, c.object, f.Kind, f.Code, f.Object)
+		}
+	}
+
+	for _, err := range []error{
+		fmt.Errorf("dial tcp: connection refused"),
+		fmt.Errorf("Fault(510042): Error on object : OBJECT_DOMAIN (CAUSE_NOTFOUND) [not an XML-RPC fault]"),
+		fmt.Errorf("request error: bad status code - 429"),
+	} {
+		if _, ok := parseGandiFault(err); ok {
+			t.Fatalf("expected %q not to be a fault", err)
+		}
+	}
+}
+
+func TestTransportRateLimited(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	c := &http.Client{Transport: &gandiTransport{next: &http.Transport{}}}
+	_, err := c.Post(server.URL, "text/xml", strings.NewReader(testTraceRequest))
+	if faultKind(err) != FaultRateLimited {
+		t.Fatalf("expected a rate limited fault, got: %v", err)
+	}
+}
+
+func TestWrapAPIError(t *testing.T) {
+	fault := xmlrpc.FaultError{Code: 510150, String: "Error on object : OBJECT_ZONE (CAUSE_NORIGHT) [Access denied]"}
+
+	err := wrapAPIError(wrapAPIError(fault, "Cannot create zone version"), "Could not create new version for record")
+	if faultKind(err) != FaultPermissionDenied {
+		t.Fatalf("expected the kind to be kept, got %s", faultKind(err))
+	}
+	expected := "Could not create new version for record: Cannot create zone version: " + fault.Error() +
+		" (API key lacks DNS permission)"
+	if err.Error() != expected {
+		t.Fatalf("expected %q, got %q", expected, err.Error())
+	}
+
+	err = wrapAPIError(fmt.Errorf("connection refused"), "Cannot create zone")
+	if err.Error() != "Cannot create zone: connection refused" || faultKind(err) != FaultUnknown {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestNewNotFound(t *testing.T) {
+	err := wrapAPIError(newNotFound("Record %d not found", 42), "Couldn't find record")
+	if !isNotFound(err) {
+		t.Fatalf("expected a not found fault")
+	}
+	if !strings.HasPrefix(err.Error(), "Couldn't find record: Record 42 not found") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// TestReadDomainGone checks the resources of a domain leave the state when
+// the domain is gone, and only then
+func TestReadDomainGone(t *testing.T) {
+	reads := map[string]struct {
+		resource *schema.Resource
+		read     func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics
+		id       string
+	}{
+		"nameservers":     {resourceDomainNameservers(), ReadDomainNameservers, "example.com"},
+		"settings":        {resourceDomainSettings(), ReadDomainSettings, "example.com"},
+		"dnssec key":      {resourceDNSSECKey(), ReadDNSSECKey, "42"},
+		"mail forward":    {resourceMailForward(), ReadMailForward, "contact@example.com"},
+		"mailbox":         {resourceMailbox(), ReadMailbox, "admin@example.com"},
+		"web redirection": {resourceWebRedirection(), ReadWebRedirection, "www.example.com"},
+	}
+
+	gone := testGandiMeta(t, http.StatusOK,
+		testXMLRPCFaultOf(510042, "Error on object : OBJECT_DOMAIN (CAUSE_NOTFOUND) [Domain 'example.com' doesn't exist.]"))
+	denied := testGandiMeta(t, http.StatusOK,
+		testXMLRPCFaultOf(510150, "Error on object : OBJECT_DOMAIN (CAUSE_NORIGHT) [Access denied]"))
+
+	for name, r := range reads {
+		d := r.resource.TestResourceData()
+		d.Set("domain", "example.com")
+
+		d.SetId(r.id)
+		if diags := r.read(context.Background(), d, denied); !diags.HasError() || d.Id() != r.id {
+			t.Fatalf("%s: expected an error and the ID kept, got: %v %q", name, diags, d.Id())
+		}
+		if diags := r.read(context.Background(), d, gone); diags.HasError() || d.Id() != "" {
+			t.Fatalf("%s: expected the resource to be removed from the state, got: %v %q", name, diags, d.Id())
+		}
+	}
+}
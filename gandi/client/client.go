@@ -0,0 +1,107 @@
+// Package client calls the Gandi XML-RPC API
+package client
+
+import (
+	"time"
+
+	"github.com/kolo/xmlrpc"
+)
+
+// SystemType selects the Gandi platform the API calls go to
+type SystemType int
+
+const (
+	Production SystemType = iota
+	Testing
+)
+
+const (
+	productionURL = "https://rpc.gandi.net/xmlrpc/"
+	testingURL    = "https://rpc.ote.gandi.net/xmlrpc/"
+)
+
+// Client holds the API key and the endpoint of the platform
+type Client struct {
+	Key string
+	Url string
+}
+
+// New returns a client of the given platform
+func New(apiKey string, system SystemType) *Client {
+	if system == Testing {
+		return &Client{Key: apiKey, Url: testingURL}
+	}
+	return &Client{Key: apiKey, Url: productionURL}
+}
+
+// Call calls an API method, the requests go through http.DefaultTransport
+func (c *Client) Call(serviceMethod string, args []interface{}, reply interface{}) error {
+	rpc, err := xmlrpc.NewClient(c.Url, nil)
+	if err != nil {
+		return err
+	}
+	defer rpc.Close()
+	return rpc.Call(serviceMethod, args, reply)
+}
+
+// Helpers decoding the members of untyped XML-RPC structs, a missing or nil
+// member decodes to the zero value
+
+// Int returns an integer member
+func Int(res map[string]interface{}, name string) int64 {
+	v, _ := res[name].(int64)
+	return v
+}
+
+// String returns a string member
+func String(res map[string]interface{}, name string) string {
+	v, _ := res[name].(string)
+	return v
+}
+
+// Bool returns a boolean member
+func Bool(res map[string]interface{}, name string) bool {
+	v, _ := res[name].(bool)
+	return v
+}
+
+// Time returns a dateTime.iso8601 member
+func Time(res map[string]interface{}, name string) time.Time {
+	v, _ := res[name].(time.Time)
+	return v
+}
+
+// Strings returns an array member of strings
+func Strings(res map[string]interface{}, name string) []string {
+	items, _ := res[name].([]interface{})
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// Ints returns an array member of integers
+func Ints(res map[string]interface{}, name string) []int64 {
+	items, _ := res[name].([]interface{})
+	result := make([]int64, 0, len(items))
+	for _, item := range items {
+		if i, ok := item.(int64); ok {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+// Structs returns the structs of an XML-RPC array
+func Structs(res []interface{}) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(res))
+	for _, item := range res {
+		if m, ok := item.(map[string]interface{}); ok {
+			result = append(result, m)
+		}
+	}
+	return result
+}
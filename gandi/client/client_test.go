@@ -0,0 +1,57 @@
+package client
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCall(t *testing.T) {
+	var request string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		request = string(body)
+		w.Write([]byte(`<?xml version="1.0"?><methodResponse><params><param><value><struct>` +
+			`<member><name>id</name><value><int>42</int></value></member>` +
+			`<member><name>name</name><value><string>example.com</string></value></member>` +
+			`<member><name>date_updated</name><value><dateTime.iso8601>20240102T03:04:05</dateTime.iso8601></value></member>` +
+			`<member><name>versions</name><value><array><data><value><int>1</int></value><value><int>2</int></value></data></array></value></member>` +
+			`<member><name>owner</name><value><nil/></value></member>` +
+			`</struct></value></param></params></methodResponse>`))
+	}))
+	defer server.Close()
+
+	c := &Client{Key: "key", Url: server.URL}
+	var res map[string]interface{}
+	if err := c.Call("domain.zone.info", []interface{}{c.Key, 42}, &res); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if !strings.Contains(request, "<methodName>domain.zone.info</methodName>") {
+		t.Fatalf("unexpected request: %s", request)
+	}
+	if Int(res, "id") != 42 || String(res, "name") != "example.com" {
+		t.Fatalf("unexpected result: %#v", res)
+	}
+	if !Time(res, "date_updated").Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Fatalf("unexpected date: %v", Time(res, "date_updated"))
+	}
+	if versions := Ints(res, "versions"); len(versions) != 2 || versions[1] != 2 {
+		t.Fatalf("unexpected versions: %v", versions)
+	}
+	if String(res, "owner") != "" || Bool(res, "missing") {
+		t.Fatalf("expected nil and missing members to be zero values")
+	}
+}
+
+func TestNew(t *testing.T) {
+	if c := New("key", Testing); c.Url != testingURL {
+		t.Fatalf("expected the test environment, got %s", c.Url)
+	}
+	if c := New("key", Production); c.Url != productionURL {
+		t.Fatalf("expected production, got %s", c.Url)
+	}
+}
@@ -0,0 +1,95 @@
+// Package domain reads the domains of the Gandi account
+package domain
+
+import (
+	"time"
+
+	"github.com/bemehow/terraform-provider-gandi/gandi/client"
+)
+
+// DomainInfoBase are the details of a domain returned by domain.info
+type DomainInfoBase struct {
+	AuthInfo             string
+	DateCreated          time.Time
+	DateRegistryCreation time.Time
+	DateRegistryEnd      time.Time
+	DateUpdated          time.Time
+	Fqdn                 string
+	Id                   int64
+	Status               []string
+	Tld                  string
+}
+
+// AutorenewInfo is the automatic renewal of a domain
+type AutorenewInfo struct {
+	Active        bool
+	Contact       string
+	Id            int64
+	ProductId     int64
+	ProductTypeId int64
+}
+
+// DomainInfoExtra are the services of a domain returned by domain.info
+type DomainInfoExtra struct {
+	Autorenew   *AutorenewInfo
+	Nameservers []string
+	Services    []string
+	ZoneId      int64
+}
+
+// DomainInfo is the result of domain.info
+type DomainInfo struct {
+	*DomainInfoBase
+	*DomainInfoExtra
+}
+
+// Domain wraps the domain.* methods
+type Domain struct {
+	*client.Client
+}
+
+// New returns the domain methods of c
+func New(c *client.Client) *Domain {
+	return &Domain{c}
+}
+
+// Info returns the details of the named domain
+func (d *Domain) Info(name string) (*DomainInfo, error) {
+	var res map[string]interface{}
+	if err := d.Call("domain.info", []interface{}{d.Key, name}, &res); err != nil {
+		return nil, err
+	}
+	return toDomainInfo(res), nil
+}
+
+func toDomainInfo(res map[string]interface{}) *DomainInfo {
+	extra := &DomainInfoExtra{
+		Nameservers: client.Strings(res, "nameservers"),
+		Services:    client.Strings(res, "services"),
+		ZoneId:      client.Int(res, "zone_id"),
+	}
+	if autorenew, ok := res["autorenew"].(map[string]interface{}); ok {
+		extra.Autorenew = &AutorenewInfo{
+			Active:        client.Bool(autorenew, "active"),
+			Contact:       client.String(autorenew, "contact"),
+			Id:            client.Int(autorenew, "id"),
+			ProductId:     client.Int(autorenew, "product_id"),
+			ProductTypeId: client.Int(autorenew, "product_type_id"),
+		}
+	}
+
+	return &DomainInfo{
+		DomainInfoBase: &DomainInfoBase{
+			AuthInfo:             client.String(res, "authinfo"),
+			DateCreated:          client.Time(res, "date_created"),
+			DateRegistryCreation: client.Time(res, "date_registry_creation"),
+			DateRegistryEnd:      client.Time(res, "date_registry_end"),
+			DateUpdated:          client.Time(res, "date_updated"),
+			Fqdn:                 client.String(res, "fqdn"),
+			Id:                   client.Int(res, "id"),
+			Status:               client.Strings(res, "status"),
+			Tld:                  client.String(res, "tld"),
+		},
+		DomainInfoExtra: extra,
+	}
+}
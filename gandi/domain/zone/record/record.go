@@ -0,0 +1,122 @@
+// Package record manages the records of a DNS zone version
+package record
+
+import (
+	"github.com/bemehow/terraform-provider-gandi/gandi/client"
+)
+
+// RecordInfo is a record of a zone version
+type RecordInfo struct {
+	Id    int64
+	Name  string
+	Ttl   int64
+	Type  string
+	Value string
+}
+
+// RecordAdd are the arguments of Add
+type RecordAdd struct {
+	Zone    int64
+	Version int64
+	Name    string
+	Type    string
+	Value   string
+	Ttl     int64
+}
+
+// RecordUpdate are the arguments of Update
+type RecordUpdate struct {
+	Zone    int64
+	Version int64
+	Name    string
+	Type    string
+	Value   string
+	Ttl     int64
+	Id      int64
+}
+
+// Record wraps the domain.zone.record.* methods
+type Record struct {
+	*client.Client
+}
+
+// New returns the record methods of c
+func New(c *client.Client) *Record {
+	return &Record{c}
+}
+
+// List returns the records of a zone version
+func (r *Record) List(zoneId, version int64) ([]*RecordInfo, error) {
+	var res []interface{}
+	if err := r.Call("domain.zone.record.list", []interface{}{r.Key, zoneId, version}, &res); err != nil {
+		return nil, err
+	}
+
+	var records []*RecordInfo
+	for _, item := range client.Structs(res) {
+		records = append(records, toRecordInfo(item))
+	}
+	return records, nil
+}
+
+// Add adds a record to an inactive zone version
+func (r *Record) Add(args RecordAdd) (*RecordInfo, error) {
+	params := recordParams(args.Name, args.Type, args.Value, args.Ttl)
+
+	var res map[string]interface{}
+	if err := r.Call("domain.zone.record.add", []interface{}{r.Key, args.Zone, args.Version, params}, &res); err != nil {
+		return nil, err
+	}
+	return toRecordInfo(res), nil
+}
+
+// Update replaces a record of an inactive zone version
+func (r *Record) Update(args RecordUpdate) ([]*RecordInfo, error) {
+	params := recordParams(args.Name, args.Type, args.Value, args.Ttl)
+	opts := map[string]interface{}{"id": args.Id}
+
+	var res []interface{}
+	if err := r.Call("domain.zone.record.update", []interface{}{r.Key, args.Zone, args.Version, opts, params}, &res); err != nil {
+		return nil, err
+	}
+
+	var records []*RecordInfo
+	for _, item := range client.Structs(res) {
+		records = append(records, toRecordInfo(item))
+	}
+	return records, nil
+}
+
+// Delete removes a record from an inactive zone version
+func (r *Record) Delete(zoneId, version, recordId int64) (bool, error) {
+	opts := map[string]interface{}{"id": recordId}
+
+	var res int64
+	if err := r.Call("domain.zone.record.delete", []interface{}{r.Key, zoneId, version, opts}, &res); err != nil {
+		return false, err
+	}
+	return res == 1, nil
+}
+
+// recordParams leaves the TTL to the zone default when it is 0
+func recordParams(name, recordType, value string, ttl int64) map[string]interface{} {
+	params := map[string]interface{}{
+		"name":  name,
+		"type":  recordType,
+		"value": value,
+	}
+	if ttl != 0 {
+		params["ttl"] = ttl
+	}
+	return params
+}
+
+func toRecordInfo(res map[string]interface{}) *RecordInfo {
+	return &RecordInfo{
+		Id:    client.Int(res, "id"),
+		Name:  client.String(res, "name"),
+		Ttl:   client.Int(res, "ttl"),
+		Type:  client.String(res, "type"),
+		Value: client.String(res, "value"),
+	}
+}
@@ -0,0 +1,65 @@
+package record
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bemehow/terraform-provider-gandi/gandi/client"
+)
+
+// testRecord returns record methods answered with response, requests are
+// appended to requests
+func testRecord(t *testing.T, response string, requests *[]string) *Record {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		*requests = append(*requests, string(body))
+		w.Write([]byte(`<?xml version="1.0"?><methodResponse><params><param><value>` +
+			response + `</value></param></params></methodResponse>`))
+	}))
+	t.Cleanup(server.Close)
+
+	return New(&client.Client{Key: "key", Url: server.URL})
+}
+
+func TestList(t *testing.T) {
+	var requests []string
+	r := testRecord(t, `<array><data><value><struct>`+
+		`<member><name>id</name><value><int>7</int></value></member>`+
+		`<member><name>name</name><value><string>www</string></value></member>`+
+		`<member><name>ttl</name><value><int>3600</int></value></member>`+
+		`<member><name>type</name><value><string>A</string></value></member>`+
+		`<member><name>value</name><value><string>192.0.2.1</string></value></member>`+
+		`</struct></value></data></array>`, &requests)
+
+	records, err := r.List(42, 3)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(records) != 1 || *records[0] != (RecordInfo{Id: 7, Name: "www", Ttl: 3600, Type: "A", Value: "192.0.2.1"}) {
+		t.Fatalf("unexpected records: %#v", records)
+	}
+}
+
+func TestUpdateAndDelete(t *testing.T) {
+	var requests []string
+	r := testRecord(t, `<int>1</int>`, &requests)
+
+	deleted, err := r.Delete(42, 3, 7)
+	if err != nil || !deleted {
+		t.Fatalf("expected the record to be deleted, got %v: %v", deleted, err)
+	}
+	if !strings.Contains(requests[0], "<name>id</name><value><int>7</int></value>") {
+		t.Fatalf("expected the record ID in the options: %s", requests[0])
+	}
+
+	r = testRecord(t, `<array><data></data></array>`, &requests)
+	if _, err := r.Update(RecordUpdate{Zone: 42, Version: 3, Id: 7, Name: "www", Type: "A", Value: "192.0.2.2"}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if strings.Contains(requests[1], "<name>ttl</name>") {
+		t.Fatalf("expected no TTL when it is 0: %s", requests[1])
+	}
+}
@@ -0,0 +1,74 @@
+// Package version manages the versions of a DNS zone
+package version
+
+import (
+	"time"
+
+	"github.com/bemehow/terraform-provider-gandi/gandi/client"
+)
+
+// VersionInfo is a version of a zone
+type VersionInfo struct {
+	DateCreated time.Time
+	Id          int64
+}
+
+// Version wraps the domain.zone.version.* methods
+type Version struct {
+	*client.Client
+}
+
+// New returns the zone version methods of c
+func New(c *client.Client) *Version {
+	return &Version{c}
+}
+
+// List returns the versions of a zone
+func (v *Version) List(zoneId int64) ([]*VersionInfo, error) {
+	var res []interface{}
+	if err := v.Call("domain.zone.version.list", []interface{}{v.Key, zoneId}, &res); err != nil {
+		return nil, err
+	}
+
+	var versions []*VersionInfo
+	for _, r := range client.Structs(res) {
+		versions = append(versions, &VersionInfo{
+			DateCreated: client.Time(r, "date_created"),
+			Id:          client.Int(r, "id"),
+		})
+	}
+	return versions, nil
+}
+
+// New creates a version of a zone as a copy of version, or of the active
+// version when version is 0, and returns its number
+func (v *Version) New(zoneId, version int64) (int64, error) {
+	args := []interface{}{v.Key, zoneId}
+	if version != 0 {
+		args = append(args, version)
+	}
+
+	var res int64
+	if err := v.Call("domain.zone.version.new", args, &res); err != nil {
+		return 0, err
+	}
+	return res, nil
+}
+
+// Delete deletes an inactive version of a zone
+func (v *Version) Delete(zoneId, version int64) (bool, error) {
+	var res bool
+	if err := v.Call("domain.zone.version.delete", []interface{}{v.Key, zoneId, version}, &res); err != nil {
+		return false, err
+	}
+	return res, nil
+}
+
+// Set activates a version of a zone
+func (v *Version) Set(zoneId, version int64) (bool, error) {
+	var res bool
+	if err := v.Call("domain.zone.version.set", []interface{}{v.Key, zoneId, version}, &res); err != nil {
+		return false, err
+	}
+	return res, nil
+}
@@ -0,0 +1,103 @@
+// Package zone manages the DNS zones of the Gandi account
+package zone
+
+import (
+	"time"
+
+	"github.com/bemehow/terraform-provider-gandi/gandi/client"
+)
+
+// ZoneInfoBase are the details of a zone listed by domain.zone.list
+type ZoneInfoBase struct {
+	DateUpdated time.Time
+	Id          int64
+	Name        string
+	Public      bool
+	Version     int64
+}
+
+// ZoneInfoExtra are the details only returned by domain.zone.info
+type ZoneInfoExtra struct {
+	Domains  int64
+	Owner    string
+	Versions []int64
+}
+
+// ZoneInfo is the result of domain.zone.info
+type ZoneInfo struct {
+	*ZoneInfoBase
+	*ZoneInfoExtra
+}
+
+// Zone wraps the domain.zone.* methods
+type Zone struct {
+	*client.Client
+}
+
+// New returns the zone methods of c
+func New(c *client.Client) *Zone {
+	return &Zone{c}
+}
+
+// Info returns the details of a zone
+func (z *Zone) Info(id int64) (*ZoneInfo, error) {
+	var res map[string]interface{}
+	if err := z.Call("domain.zone.info", []interface{}{z.Key, id}, &res); err != nil {
+		return nil, err
+	}
+	return toZoneInfo(res), nil
+}
+
+// List returns the zones of the account
+func (z *Zone) List() ([]*ZoneInfoBase, error) {
+	var res []interface{}
+	if err := z.Call("domain.zone.list", []interface{}{z.Key}, &res); err != nil {
+		return nil, err
+	}
+
+	var zones []*ZoneInfoBase
+	for _, r := range client.Structs(res) {
+		zones = append(zones, toZoneInfoBase(r))
+	}
+	return zones, nil
+}
+
+// Create creates an empty zone
+func (z *Zone) Create(name string) (*ZoneInfo, error) {
+	params := map[string]interface{}{"name": name}
+	var res map[string]interface{}
+	if err := z.Call("domain.zone.create", []interface{}{z.Key, params}, &res); err != nil {
+		return nil, err
+	}
+	return toZoneInfo(res), nil
+}
+
+// Delete deletes a zone, Gandi refuses it while domains use the zone
+func (z *Zone) Delete(id int64) (bool, error) {
+	var res bool
+	if err := z.Call("domain.zone.delete", []interface{}{z.Key, id}, &res); err != nil {
+		return false, err
+	}
+	return res, nil
+}
+
+func toZoneInfoBase(res map[string]interface{}) *ZoneInfoBase {
+	return &ZoneInfoBase{
+		DateUpdated: client.Time(res, "date_updated"),
+		Id:          client.Int(res, "id"),
+		Name:        client.String(res, "name"),
+		Public:      client.Bool(res, "public"),
+		Version:     client.Int(res, "version"),
+	}
+}
+
+func toZoneInfo(res map[string]interface{}) *ZoneInfo {
+	return &ZoneInfo{
+		ZoneInfoBase: toZoneInfoBase(res),
+		ZoneInfoExtra: &ZoneInfoExtra{
+			Domains:  client.Int(res, "domains"),
+			Owner:    client.String(res, "owner"),
+			Versions: client.Ints(res, "versions"),
+		},
+	}
+}
@@ -0,0 +1,54 @@
+// Package operation reads the asynchronous operations of the Gandi API
+package operation
+
+import (
+	"time"
+
+	"github.com/bemehow/terraform-provider-gandi/gandi/client"
+)
+
+// OperationInfo is the state of an operation
+type OperationInfo struct {
+	DateCreated time.Time
+	DateStart   time.Time
+	DateUpdated time.Time
+	Eta         int64
+	Id          int64
+	LastError   string
+	SourceId    int64
+	Step        string
+	Type        string
+}
+
+// Operation wraps the operation.* methods
+type Operation struct {
+	*client.Client
+}
+
+// New returns the operation methods of c
+func New(c *client.Client) *Operation {
+	return &Operation{c}
+}
+
+// Info returns the state of an operation
+func (o *Operation) Info(id int64) (*OperationInfo, error) {
+	var res map[string]interface{}
+	if err := o.Call("operation.info", []interface{}{o.Key, id}, &res); err != nil {
+		return nil, err
+	}
+	return toOperationInfo(res), nil
+}
+
+func toOperationInfo(res map[string]interface{}) *OperationInfo {
+	return &OperationInfo{
+		DateCreated: client.Time(res, "date_created"),
+		DateStart:   client.Time(res, "date_start"),
+		DateUpdated: client.Time(res, "date_updated"),
+		Eta:         client.Int(res, "eta"),
+		Id:          client.Int(res, "id"),
+		LastError:   client.String(res, "last_error"),
+		SourceId:    client.Int(res, "source_id"),
+		Step:        client.String(res, "step"),
+		Type:        client.String(res, "type"),
+	}
+}
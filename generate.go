@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bemehow/terraform-provider-gandi/gandi/domain/zone"
+	"github.com/bemehow/terraform-provider-gandi/gandi/domain/zone/record"
+)
+
+// ZoneConfig holds what is needed to generate the configuration of one zone
+type ZoneConfig struct {
+	Zone    *zone.ZoneInfoBase
+	Records []*record.RecordInfo
+}
+
+// ListZoneConfigs looks up the named zones and the records of their active
+// versions
+func ListZoneConfigs(meta interface{}, names []string) ([]ZoneConfig, error) {
+	zones, err := getZoneClient(meta).List()
+	if err != nil {
+		return nil, wrapAPIError(err, "Cannot list zones")
+	}
+
+	byName := make(map[string]*zone.ZoneInfoBase)
+	for _, z := range zones {
+		byName[z.Name] = z
+	}
+
+	var configs []ZoneConfig
+	for _, name := range names {
+		z, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("Zone not found: %s", name)
+		}
+
+		log.Printf("[DEBUG] Reading records from zone: %v version: %v", z.Id, z.Version)
+		records, err := listRecords(meta, z.Id, z.Version)
+		if err != nil {
+			return nil, wrapAPIError(err, "Cannot read records from zone: %v version: %v", z.Id, z.Version)
+		}
+
+		configs = append(configs, ZoneConfig{Zone: z, Records: records})
+	}
+
+	return configs, nil
+}
+
+// GenerateConfig writes HCL for gandi_zone and gandi_record resources and
+// the terraform import commands adopting the existing objects
+func GenerateConfig(configs []ZoneConfig) (string, string) {
+	var hcl, imports bytes.Buffer
+	names := make(map[string]bool)
+
+	for _, c := range configs {
+		zoneName := uniqueResourceName(names, c.Zone.Name)
+
+		fmt.Fprintf(&hcl, "# Zone %d, generated from active version %d\n", c.Zone.Id, c.Zone.Version)
+		fmt.Fprintf(&hcl, "resource \"gandi_zone\" %q {\n", zoneName)
+		fmt.Fprintf(&hcl, "  name = %s\n", hclString(c.Zone.Name))
+		fmt.Fprintf(&hcl, "}\n\n")
+		fmt.Fprintf(&imports, "terraform import gandi_zone.%s %d\n", zoneName, c.Zone.Id)
+
+		records := make([]*record.RecordInfo, len(c.Records))
+		copy(records, c.Records)
+		sort.SliceStable(records, func(i, j int) bool {
+			if records[i].Name != records[j].Name {
+				return records[i].Name < records[j].Name
+			}
+			return records[i].Type < records[j].Type
+		})
+
+		for _, r := range records {
+			recordName := uniqueResourceName(names, c.Zone.Name+"_"+r.Name+"_"+r.Type)
+
+			fmt.Fprintf(&hcl, "resource \"gandi_record\" %q {\n", recordName)
+			fmt.Fprintf(&hcl, "  zone_id = gandi_zone.%s.id\n", zoneName)
+			fmt.Fprintf(&hcl, "  name    = %s\n", hclString(r.Name))
+			fmt.Fprintf(&hcl, "  type    = %s\n", hclString(r.Type))
+			fmt.Fprintf(&hcl, "  value   = %s\n", hclString(unquoteRecordValue(r.Value)))
+			fmt.Fprintf(&hcl, "  ttl     = %s\n", strconv.FormatInt(r.Ttl, 10))
+			fmt.Fprintf(&hcl, "}\n\n")
+			fmt.Fprintf(&imports, "terraform import gandi_record.%s %d_%d\n", recordName, c.Zone.Id, r.Id)
+		}
+	}
+
+	return hcl.String(), imports.String()
+}
+
+// uniqueResourceName turns s into a resource name not yet present in names
+func uniqueResourceName(names map[string]bool, s string) string {
+	var buf bytes.Buffer
+	for _, c := range strings.ToLower(s) {
+		switch {
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9', c == '_', c == '-':
+			buf.WriteRune(c)
+		case c == '@':
+			buf.WriteString("apex")
+		default:
+			buf.WriteRune('_')
+		}
+	}
+
+	name := strings.Trim(buf.String(), "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') || name[0] == '-' {
+		name = "gandi_" + name
+	}
+
+	unique := name
+	for i := 2; names[unique]; i++ {
+		unique = name + "_" + strconv.Itoa(i)
+	}
+	names[unique] = true
+
+	return unique
+}
+
+// hclString quotes s as an HCL string. HCL has no \x escape, so control
+// characters are written as \u escapes, and the template sequences ${ and
+// %{ are doubled to stay literal.
+func hclString(s string) string {
+	var buf bytes.Buffer
+	buf.WriteByte('"')
+	for i, c := range s {
+		switch {
+		case c == '"':
+			buf.WriteString(`\"`)
+		case c == '\\':
+			buf.WriteString(`\\`)
+		case c == '\n':
+			buf.WriteString(`\n`)
+		case c == '\r':
+			buf.WriteString(`\r`)
+		case c == '\t':
+			buf.WriteString(`\t`)
+		case (c == '$' || c == '%') && strings.HasPrefix(s[i+1:], "{"):
+			buf.WriteRune(c)
+			buf.WriteRune(c)
+		case c < 0x20 || c == 0x7f || (c >= 0x80 && c < 0xa0):
+			fmt.Fprintf(&buf, `\u%04x`, c)
+		default:
+			// invalid UTF-8 is written as U+FFFD
+			buf.WriteRune(c)
+		}
+	}
+	buf.WriteByte('"')
+	return buf.String()
+}
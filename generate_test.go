@@ -0,0 +1,117 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bemehow/terraform-provider-gandi/gandi/domain/zone"
+	"github.com/bemehow/terraform-provider-gandi/gandi/domain/zone/record"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+func TestGenerateConfig(t *testing.T) {
+	configs := []ZoneConfig{
+		ZoneConfig{
+			Zone: &zone.ZoneInfoBase{Id: 42, Name: "example.com", Version: 3},
+			Records: []*record.RecordInfo{
+				{Id: 7, Name: "www", Type: "A", Value: "1.1.1.1", Ttl: 3600},
+				{Id: 8, Name: "www", Type: "A", Value: "2.2.2.2", Ttl: 3600},
+				{Id: 9, Name: "@", Type: "TXT", Value: `"price ${var}"`, Ttl: 300},
+			},
+		},
+	}
+
+	hcl, imports := GenerateConfig(configs)
+
+	expectedHCL := `# Zone 42, generated from active version 3
+resource "gandi_zone" "example_com" {
+  name = "example.com"
+}
+
+resource "gandi_record" "example_com_apex_txt" {
+  zone_id = gandi_zone.example_com.id
+  name    = "@"
+  type    = "TXT"
+  value   = "price $${var}"
+  ttl     = 300
+}
+
+resource "gandi_record" "example_com_www_a" {
+  zone_id = gandi_zone.example_com.id
+  name    = "www"
+  type    = "A"
+  value   = "1.1.1.1"
+  ttl     = 3600
+}
+
+resource "gandi_record" "example_com_www_a_2" {
+  zone_id = gandi_zone.example_com.id
+  name    = "www"
+  type    = "A"
+  value   = "2.2.2.2"
+  ttl     = 3600
+}
+
+`
+	if hcl != expectedHCL {
+		t.Fatalf("unexpected configuration:\n%s\nwant:\n%s", hcl, expectedHCL)
+	}
+
+	expectedImports := strings.Join([]string{
+		"terraform import gandi_zone.example_com 42",
+		"terraform import gandi_record.example_com_apex_txt 42_9",
+		"terraform import gandi_record.example_com_www_a 42_7",
+		"terraform import gandi_record.example_com_www_a_2 42_8",
+		"",
+	}, "\n")
+	if imports != expectedImports {
+		t.Fatalf("unexpected imports:\n%s\nwant:\n%s", imports, expectedImports)
+	}
+}
+
+func TestUniqueResourceName(t *testing.T) {
+	names := make(map[string]bool)
+	cases := []struct {
+		in, out string
+	}{
+		{"example.com", "example_com"},
+		{"example.com", "example_com_2"},
+		{"1st.example", "gandi_1st_example"},
+		{"*.wildcard", "wildcard"},
+	}
+
+	for _, c := range cases {
+		if got := uniqueResourceName(names, c.in); got != c.out {
+			t.Fatalf("uniqueResourceName(%q) = %q, want %q", c.in, got, c.out)
+		}
+	}
+}
+
+func TestHCLString(t *testing.T) {
+	cases := []string{
+		"example.com",
+		`v=spf1 include:_spf.example.com ~all`,
+		`quote " and backslash \\`,
+		"price ${var} and %{if x}",
+		"already escaped $${var} and %%{x}",
+		"lines\nand\ttabs\r",
+		"bell \x07 and delete \x7f",
+		"unicode é and ∑",
+	}
+
+	for _, c := range cases {
+		quoted := hclString(c)
+		expr, diags := hclsyntax.ParseExpression([]byte(quoted), "test.tf", hcl.InitialPos)
+		if diags.HasErrors() {
+			t.Fatalf("hclString(%q) = %s does not parse: %s", c, quoted, diags)
+		}
+		value, diags := expr.Value(nil)
+		if diags.HasErrors() {
+			t.Fatalf("hclString(%q) = %s does not evaluate: %s", c, quoted, diags)
+		}
+		if value.AsString() != c {
+			t.Fatalf("hclString(%q) = %s evaluates to %q", c, quoted, value.AsString())
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"io"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/logutils"
+)
+
+// redactedValue replaces secrets in log output
+const redactedValue = "[redacted]"
+
+// logSecrets holds the credentials configured in this process, they are
+// removed from everything written through the standard logger
+var logSecrets = &secrets{}
+
+type secrets struct {
+	sync.RWMutex
+	values []string
+}
+
+// Register adds a secret to be redacted from the logs
+func (s *secrets) Register(secret string) {
+	if secret == "" {
+		return
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	for _, v := range s.values {
+		if v == secret {
+			return
+		}
+	}
+	s.values = append(s.values, secret)
+}
+
+// Redact replaces every registered secret found in text
+func (s *secrets) Redact(text string) string {
+	s.RLock()
+	defer s.RUnlock()
+	for _, v := range s.values {
+		text = strings.Replace(text, v, redactedValue, -1)
+	}
+	return text
+}
+
+// redactingWriter redacts registered secrets before writing log lines
+type redactingWriter struct {
+	w io.Writer
+}
+
+func (r *redactingWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(r.w, logSecrets.Redact(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// setupLogging makes the standard logger redact registered secrets
+func setupLogging() {
+	if _, ok := log.Writer().(*redactingWriter); ok {
+		return
+	}
+	log.SetOutput(&redactingWriter{w: log.Writer()})
+}
+
+// logLevels are the levels of the log lines, lowest first
+var logLevels = []logutils.LogLevel{"TRACE", "DEBUG", "INFO", "WARN", "ERROR"}
+
+// commandLogLevel returns the lowest level logged by commands for the
+// TF_LOG value level, WARN when unset and TRACE when not a level, as
+// Terraform does
+func commandLogLevel(level string) logutils.LogLevel {
+	if level == "" {
+		return "WARN"
+	}
+	for _, l := range logLevels {
+		if string(l) == strings.ToUpper(level) {
+			return l
+		}
+	}
+	return "TRACE"
+}
+
+// setupCommandLogging makes commands log to stderr from the level set in
+// TF_LOG, with registered secrets redacted
+func setupCommandLogging() {
+	log.SetOutput(&logutils.LevelFilter{
+		Levels:   logLevels,
+		MinLevel: commandLogLevel(os.Getenv("TF_LOG")),
+		Writer:   os.Stderr,
+	})
+	setupLogging()
+}
+
+// setupPluginLogging sends the standard logger to Terraform the way
+// plugin.Serve does, as JSON lines carrying the level, with registered
+// secrets redacted
+func setupPluginLogging() {
+	logger := hclog.New(&hclog.LoggerOptions{
+		Level:      hclog.Trace,
+		JSONFormat: true,
+	})
+	log.SetOutput(logger.StandardWriter(&hclog.StandardLoggerOptions{InferLevels: true}))
+	setupLogging()
+}
+
+// xmlrpcSecretMember matches struct members of XML-RPC calls holding
+// credentials, e.g. passwords of contacts and mailboxes
+var xmlrpcSecretMember = regexp.MustCompile(
+	`(?s)(<name>(?:password|authinfo|auth_info)</name>\s*<value>(?:\s*<string>)?).*?((?:</string>\s*)?</value>)`)
+
+// redactXMLRPC removes the API key and credential members from an XML-RPC
+// request or response before it is dumped to the logs
+func redactXMLRPC(body string) string {
+	body = xmlrpcSecretMember.ReplaceAllString(body, "${1}"+redactedValue+"${2}")
+	return logSecrets.Redact(body)
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/logutils"
+)
+
+const testLogKey = "0123456789abcdefTESTKEY"
+
+// captureLog returns what f logs through the writer built by wrap
+func captureLog(wrap func(io.Writer) io.Writer, f func()) string {
+	var buf bytes.Buffer
+	out := log.Writer()
+	log.SetOutput(wrap(&buf))
+	defer log.SetOutput(out)
+
+	f()
+	return buf.String()
+}
+
+func rawWriter(w io.Writer) io.Writer { return w }
+
+func redacting(w io.Writer) io.Writer { return &redactingWriter{w: w} }
+
+func TestConfigClientDoesNotLogKey(t *testing.T) {
+	output := captureLog(rawWriter, func() {
+		config := Config{Key: testLogKey, Testing: true}
+		config.Client()
+	})
+
+	if strings.Contains(output, testLogKey) {
+		t.Fatalf("API key found in log output: %s", output)
+	}
+	if !strings.Contains(output, "Gandi Client configured") {
+		t.Fatalf("expected the client configuration to be logged, got: %s", output)
+	}
+}
+
+func TestRedactingWriter(t *testing.T) {
+	logSecrets.Register(testLogKey)
+
+	output := captureLog(redacting, func() {
+		log.Printf("[DEBUG] calling with %s and %q", testLogKey, testLogKey)
+		log.Printf("[DEBUG] %#v", []interface{}{testLogKey, 42})
+	})
+
+	if strings.Contains(output, testLogKey) {
+		t.Fatalf("API key found in log output: %s", output)
+	}
+	if count := strings.Count(output, redactedValue); count != 3 {
+		t.Fatalf("expected 3 redactions, got %d: %s", count, output)
+	}
+}
+
+func TestTransportRedactsXMLRPC(t *testing.T) {
+	logSecrets.Register(testLogKey)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<?xml version="1.0"?>
+<methodResponse><params><param><value><struct>
+<member><name>handle</name><value><string>AB1234-GANDI</string></value></member>
+<member><name>key</name><value><string>%s</string></value></member>
+</struct></value></param></params></methodResponse>`, testLogKey)
+	}))
+	defer server.Close()
+
+	request := fmt.Sprintf(`<?xml version="1.0"?>
+<methodCall><methodName>contact.create</methodName><params>
+<param><value><string>%s</string></value></param>
+<param><value><struct>
+<member><name>password</name><value><string>hunter2-secret</string></value></member>
+<member><name>email</name><value><string>admin@example.com</string></value></member>
+</struct></value></param>
+</params></methodCall>`, testLogKey)
+
+	var body []byte
+	output := captureLog(redacting, func() {
+		client := &http.Client{Transport: &gandiTransport{next: http.DefaultTransport}}
+		resp, err := client.Post(server.URL, "text/xml", strings.NewReader(request))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		defer resp.Body.Close()
+		body, _ = ioutil.ReadAll(resp.Body)
+	})
+
+	if !strings.Contains(string(body), testLogKey) {
+		t.Fatalf("the response must reach the client untouched, got: %s", body)
+	}
+	for _, secret := range []string{testLogKey, "hunter2-secret"} {
+		if strings.Contains(output, secret) {
+			t.Fatalf("%q found in log output: %s", secret, output)
+		}
+	}
+	if !strings.Contains(output, "admin@example.com") {
+		t.Fatalf("expected the request to be dumped, got: %s", output)
+	}
+}
+
+func TestCommandLogLevel(t *testing.T) {
+	cases := map[string]string{
+		"":      "WARN",
+		"debug": "DEBUG",
+		"ERROR": "ERROR",
+		"1":     "TRACE",
+	}
+	for value, expected := range cases {
+		if level := commandLogLevel(value); string(level) != expected {
+			t.Fatalf("expected %s for TF_LOG=%q, got %s", expected, value, level)
+		}
+	}
+}
+
+func TestCommandLoggingFilters(t *testing.T) {
+	filter := func(w io.Writer) io.Writer {
+		return &logutils.LevelFilter{Levels: logLevels, MinLevel: commandLogLevel(""), Writer: w}
+	}
+	output := captureLog(filter, func() {
+		log.Printf("[DEBUG] calling domain.info")
+		log.Printf("[WARN] Cannot write API metrics")
+	})
+
+	if strings.Contains(output, "domain.info") {
+		t.Fatalf("expected DEBUG lines to be filtered: %s", output)
+	}
+	if !strings.Contains(output, "Cannot write API metrics") {
+		t.Fatalf("expected WARN lines to be logged: %s", output)
+	}
+}
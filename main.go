@@ -17,23 +17,57 @@ limitations under the License.
 package main
 
 import (
-	"github.com/hashicorp/terraform/helper/schema"
-	"github.com/hashicorp/terraform/plugin"
-	"github.com/hashicorp/terraform/terraform"
+	"context"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
 )
 
 func main() {
+	// Terraform starts plugins without arguments, anything else is a command
+	if len(os.Args) > 1 {
+		setupCommandLogging()
+		// a command is a run of its own
+		apiMetrics.SetRun(strconv.Itoa(os.Getpid()))
+		code := runCommand(os.Args[1], os.Args[2:])
+		reportMetrics()
+		os.Exit(code)
+	}
+
+	setupPluginLogging()
 	plugin.Serve(&plugin.ServeOpts{
-		ProviderFunc: func() terraform.ResourceProvider {
-			return Provider()
-		},
+		ProviderFunc: Provider,
+		// the log output set up above redacts secrets
+		NoLogOutputOverride: true,
 	})
+	reportMetrics()
 }
 
-func providerConfigure(d *schema.ResourceData) (interface{}, error) {
+func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 	config := Config{
-		Key:     d.Get("key").(string),
-		Testing: d.Get("testing").(bool),
+		Key:             d.Get("key").(string),
+		KeyFile:         d.Get("key_file").(string),
+		Profile:         d.Get("profile").(string),
+		CredentialsFile: d.Get("credentials_file").(string),
+		Testing:         d.Get("testing").(bool),
+		MetricsFile:     d.Get("metrics_file").(string),
+		Trace:           d.Get("trace").(bool),
+		TraceFile:       d.Get("trace_file").(string),
+	}
+	// Checked by validateDuration
+	config.OperationTimeout, _ = time.ParseDuration(d.Get("operation_timeout").(string))
+	config.APITimeout, _ = time.ParseDuration(d.Get("api_timeout").(string))
+	if err := config.LoadKey(); err != nil {
+		return nil, diag.FromErr(err)
+	}
+
+	meta, err := config.Meta(ctx)
+	if err != nil {
+		return nil, diag.FromErr(err)
 	}
-	return config.Client(), nil
+	return meta, nil
 }
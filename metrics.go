@@ -0,0 +1,342 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var (
+	xmlrpcMethodName = regexp.MustCompile(`<methodName>\s*([^<\s]+)\s*</methodName>`)
+	xmlrpcFault      = regexp.MustCompile(`<fault>`)
+)
+
+// xmlrpcMethod returns the method called by an XML-RPC request
+func xmlrpcMethod(body string) string {
+	if m := xmlrpcMethodName.FindStringSubmatch(body); m != nil {
+		return m[1]
+	}
+	return "unknown"
+}
+
+// isXMLRPCFault reports whether an XML-RPC response is a fault
+func isXMLRPCFault(body string) bool {
+	return xmlrpcFault.MatchString(body)
+}
+
+// MethodMetrics are the counters of one XML-RPC method
+type MethodMetrics struct {
+	Calls  int64 `json:"calls"`
+	Faults int64 `json:"faults"`
+	Errors int64 `json:"errors"`
+
+	TotalLatency time.Duration `json:"-"`
+	MaxLatency   time.Duration `json:"-"`
+
+	TotalMs float64 `json:"total_ms"`
+	MeanMs  float64 `json:"mean_ms"`
+	MaxMs   float64 `json:"max_ms"`
+}
+
+// add sums the counters of other into mm and recomputes the mean
+func (mm *MethodMetrics) add(other MethodMetrics) {
+	mm.Calls += other.Calls
+	mm.Faults += other.Faults
+	mm.Errors += other.Errors
+	mm.TotalMs += other.TotalMs
+	if other.MaxMs > mm.MaxMs {
+		mm.MaxMs = other.MaxMs
+	}
+	mm.MeanMs = 0
+	if mm.Calls > 0 {
+		mm.MeanMs = mm.TotalMs / float64(mm.Calls)
+	}
+}
+
+const (
+	// metricsFileInterval is the shortest time between two writes of the
+	// metrics file
+	metricsFileInterval = time.Second
+
+	// metricsLockStale is the age after which the lock of a metrics file is
+	// assumed to be left over by a killed process
+	metricsLockStale = 10 * time.Second
+)
+
+// APIMetrics counts the calls made to the Gandi API by this process
+type APIMetrics struct {
+	sync.Mutex
+	Started time.Time
+	methods map[string]*MethodMetrics
+
+	// process keys the counters of this process in the metrics file, run
+	// identifies the processes whose counters are merged with them
+	process string
+	run     string
+	file    string
+	written time.Time
+	pending *time.Timer
+}
+
+// processMetrics are the counters of one process in the metrics file
+type processMetrics struct {
+	Started time.Time                `json:"started"`
+	Updated time.Time                `json:"updated"`
+	Methods map[string]MethodMetrics `json:"methods"`
+}
+
+// metricsReport is the content of the metrics file, shared by every
+// process of a Terraform run
+type metricsReport struct {
+	Run       string                    `json:"run"`
+	Updated   time.Time                 `json:"updated"`
+	Methods   map[string]MethodMetrics  `json:"methods"`
+	Processes map[string]processMetrics `json:"processes"`
+}
+
+// apiMetrics collects the calls going through gandiTransport
+var apiMetrics = NewAPIMetrics()
+
+// NewAPIMetrics returns empty metrics
+func NewAPIMetrics() *APIMetrics {
+	return &APIMetrics{
+		Started: time.Now(),
+		methods: make(map[string]*MethodMetrics),
+		process: strconv.Itoa(os.Getpid()),
+		// Terraform starts the provider processes of a command
+		run: strconv.Itoa(os.Getppid()),
+	}
+}
+
+// SetRun changes the run the counters are merged into, by default the
+// parent process, i.e. the Terraform command that started the plugin
+func (m *APIMetrics) SetRun(run string) {
+	m.Lock()
+	defer m.Unlock()
+	m.run = run
+}
+
+// SetFile makes the metrics be written as JSON to path at most once per
+// metricsFileInterval. Terraform kills plugins without notice so the file is
+// kept current. Every provider process of a run, one per alias, merges its
+// counters into the file under its pid, the counters of other runs are
+// dropped.
+func (m *APIMetrics) SetFile(path string) {
+	m.Lock()
+	defer m.Unlock()
+	m.file = path
+}
+
+// Record counts a call, fault is set when Gandi answered with a fault and
+// failed when no answer was received
+func (m *APIMetrics) Record(method string, latency time.Duration, fault bool, failed bool) {
+	m.Lock()
+	defer m.Unlock()
+
+	mm, ok := m.methods[method]
+	if !ok {
+		mm = &MethodMetrics{}
+		m.methods[method] = mm
+	}
+	mm.Calls++
+	if fault {
+		mm.Faults++
+	}
+	if failed {
+		mm.Errors++
+	}
+	mm.TotalLatency += latency
+	if latency > mm.MaxLatency {
+		mm.MaxLatency = latency
+	}
+
+	if m.file != "" {
+		m.scheduleWrite()
+	}
+}
+
+// scheduleWrite writes the metrics file now, or at the end of the interval
+// when it was written less than metricsFileInterval ago. The caller holds
+// the lock.
+func (m *APIMetrics) scheduleWrite() {
+	if m.pending != nil {
+		return
+	}
+	wait := metricsFileInterval - time.Since(m.written)
+	if wait <= 0 {
+		m.flush()
+		return
+	}
+	m.pending = time.AfterFunc(wait, func() {
+		m.Lock()
+		defer m.Unlock()
+		m.pending = nil
+		m.flush()
+	})
+}
+
+// flush writes the metrics file, the caller holds the lock
+func (m *APIMetrics) flush() {
+	m.written = time.Now()
+	if err := m.writeFile(); err != nil {
+		log.Printf("[WARN] Cannot write API metrics to %s: %v", m.file, err)
+	}
+}
+
+// Flush writes the metrics file if a write is pending
+func (m *APIMetrics) Flush() {
+	m.Lock()
+	defer m.Unlock()
+	if m.pending == nil {
+		return
+	}
+	m.pending.Stop()
+	m.pending = nil
+	m.flush()
+}
+
+// snapshot copies the counters with their latencies in milliseconds, the
+// caller holds the lock
+func (m *APIMetrics) snapshot() map[string]MethodMetrics {
+	result := make(map[string]MethodMetrics, len(m.methods))
+	for method, mm := range m.methods {
+		s := *mm
+		s.TotalMs = float64(mm.TotalLatency) / float64(time.Millisecond)
+		s.MaxMs = float64(mm.MaxLatency) / float64(time.Millisecond)
+		if mm.Calls > 0 {
+			s.MeanMs = s.TotalMs / float64(mm.Calls)
+		}
+		result[method] = s
+	}
+	return result
+}
+
+// lockMetricsFile takes a lock file next to path so that processes don't
+// overwrite each other's counters, it returns the function releasing it
+func lockMetricsFile(path string) (func(), error) {
+	lock := path + ".lock"
+	deadline := time.Now().Add(metricsFileInterval)
+	for {
+		f, err := os.OpenFile(lock, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(lock) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		if info, err := os.Stat(lock); err == nil && time.Since(info.ModTime()) > metricsLockStale {
+			os.Remove(lock)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("Cannot lock %s: held by another process", lock)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// writeFile merges the counters of this process into the metrics file, the
+// caller holds the lock
+func (m *APIMetrics) writeFile() error {
+	unlock, err := lockMetricsFile(m.file)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	// Only the processes of the current run are kept, the file is replaced
+	// when it is unreadable or written by another run
+	var report metricsReport
+	if data, err := ioutil.ReadFile(m.file); err == nil {
+		if err := json.Unmarshal(data, &report); err != nil {
+			log.Printf("[DEBUG] Replacing unreadable API metrics file %s: %v", m.file, err)
+			report = metricsReport{}
+		}
+	}
+	if report.Run != m.run || report.Processes == nil {
+		report = metricsReport{Run: m.run, Processes: make(map[string]processMetrics)}
+	}
+
+	now := time.Now()
+	report.Updated = now
+	report.Processes[m.process] = processMetrics{m.Started, now, m.snapshot()}
+	report.Methods = make(map[string]MethodMetrics)
+	for _, p := range report.Processes {
+		for method, s := range p.Methods {
+			total := report.Methods[method]
+			total.add(s)
+			report.Methods[method] = total
+		}
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write then rename so readers never see a partial file
+	tmp, err := ioutil.TempFile(filepath.Dir(m.file), ".gandi-metrics")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), m.file)
+}
+
+// Summary returns a table of the methods called, most called first
+func (m *APIMetrics) Summary() string {
+	m.Lock()
+	defer m.Unlock()
+
+	snapshot := m.snapshot()
+	methods := make([]string, 0, len(snapshot))
+	var calls, faults, errors int64
+	for method, s := range snapshot {
+		methods = append(methods, method)
+		calls += s.Calls
+		faults += s.Faults
+		errors += s.Errors
+	}
+	sort.Slice(methods, func(i, j int) bool {
+		a, b := snapshot[methods[i]], snapshot[methods[j]]
+		if a.Calls != b.Calls {
+			return a.Calls > b.Calls
+		}
+		return methods[i] < methods[j]
+	})
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "Gandi API calls: %d, faults: %d, errors: %d in %s\n",
+		calls, faults, errors, time.Since(m.Started).Round(time.Millisecond))
+	for _, method := range methods {
+		s := snapshot[method]
+		fmt.Fprintf(&buf, "  %-32s calls: %5d faults: %3d errors: %3d mean: %8.1fms max: %8.1fms\n",
+			method, s.Calls, s.Faults, s.Errors, s.MeanMs, s.MaxMs)
+	}
+	return buf.String()
+}
+
+// reportMetrics writes the pending metrics and logs the summary of the API
+// calls made by this process
+func reportMetrics() {
+	apiMetrics.Flush()
+	log.Printf("[INFO] %s", apiMetrics.Summary())
+}
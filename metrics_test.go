@@ -0,0 +1,178 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testXMLRPCFault = `<?xml version="1.0"?>
+<methodResponse><fault><value><struct>
+<member><name>faultCode</name><value><int>510042</int></value></member>
+<member><name>faultString</name><value><string>Error on object : OBJECT_DOMAIN (CAUSE_NOTFOUND) [Domain 'example.com' doesn't exist.]</string></value></member>
+</struct></value></fault></methodResponse>`
+
+func TestXMLRPCMethod(t *testing.T) {
+	body := `<?xml version="1.0"?><methodCall><methodName>domain.zone.record.list</methodName><params/></methodCall>`
+	if method := xmlrpcMethod(body); method != "domain.zone.record.list" {
+		t.Fatalf("expected domain.zone.record.list, got %s", method)
+	}
+	if method := xmlrpcMethod("garbage"); method != "unknown" {
+		t.Fatalf("expected unknown, got %s", method)
+	}
+
+	if !isXMLRPCFault(testXMLRPCFault) {
+		t.Fatalf("expected a fault")
+	}
+	if isXMLRPCFault(`<methodResponse><params/></methodResponse>`) {
+		t.Fatalf("expected no fault")
+	}
+}
+
+func TestAPIMetricsSummary(t *testing.T) {
+	m := NewAPIMetrics()
+	m.Record("domain.info", 10*time.Millisecond, false, false)
+	m.Record("domain.zone.record.list", 20*time.Millisecond, false, false)
+	m.Record("domain.zone.record.list", 40*time.Millisecond, true, false)
+	m.Record("domain.zone.info", time.Second, false, true)
+
+	summary := m.Summary()
+	if !strings.HasPrefix(summary, "Gandi API calls: 4, faults: 1, errors: 1") {
+		t.Fatalf("unexpected summary header: %s", summary)
+	}
+
+	lines := strings.Split(strings.TrimSpace(summary), "\n")
+	if len(lines) != 4 || !strings.Contains(lines[1], "domain.zone.record.list") {
+		t.Fatalf("expected the most called method first: %s", summary)
+	}
+	if !strings.Contains(lines[1], "mean:     30.0ms max:     40.0ms") {
+		t.Fatalf("unexpected latencies: %s", lines[1])
+	}
+}
+
+func readMetricsFile(t *testing.T, path string) metricsReport {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	var report metricsReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	return report
+}
+
+func TestAPIMetricsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.json")
+
+	m := NewAPIMetrics()
+	m.SetFile(path)
+	m.Record("domain.info", 10*time.Millisecond, false, false)
+	m.Record("domain.info", 30*time.Millisecond, true, false)
+
+	// the second call is within the interval, written by the flush
+	s := readMetricsFile(t, path).Methods["domain.info"]
+	if s.Calls != 1 {
+		t.Fatalf("expected the second write to be throttled: %#v", s)
+	}
+	m.Flush()
+
+	s = readMetricsFile(t, path).Methods["domain.info"]
+	if s.Calls != 2 || s.Faults != 1 || s.MeanMs != 20 || s.MaxMs != 30 {
+		t.Fatalf("unexpected metrics: %#v", s)
+	}
+	if _, err := os.Stat(path + ".lock"); !os.IsNotExist(err) {
+		t.Fatalf("expected the lock to be released: %v", err)
+	}
+}
+
+func TestAPIMetricsFileProcesses(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.json")
+
+	first, second := NewAPIMetrics(), NewAPIMetrics()
+	first.process, second.process = "100", "200"
+	first.run, second.run = "1", "1"
+	first.SetFile(path)
+	second.SetFile(path)
+
+	first.Record("domain.info", 10*time.Millisecond, false, false)
+	second.Record("domain.info", 50*time.Millisecond, true, false)
+	second.Record("domain.zone.info", 20*time.Millisecond, false, false)
+	second.Flush()
+
+	report := readMetricsFile(t, path)
+	if len(report.Processes) != 2 {
+		t.Fatalf("expected both processes, got %#v", report.Processes)
+	}
+	if s := report.Processes["100"].Methods["domain.info"]; s.Calls != 1 {
+		t.Fatalf("unexpected metrics of the first process: %#v", s)
+	}
+
+	s := report.Methods["domain.info"]
+	if s.Calls != 2 || s.Faults != 1 || s.MeanMs != 30 || s.MaxMs != 50 {
+		t.Fatalf("unexpected merged metrics: %#v", s)
+	}
+	if s := report.Methods["domain.zone.info"]; s.Calls != 1 {
+		t.Fatalf("unexpected merged metrics: %#v", s)
+	}
+}
+
+func TestAPIMetricsFileRuns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.json")
+
+	previous := NewAPIMetrics()
+	previous.process, previous.run = "100", "1"
+	previous.SetFile(path)
+	previous.Record("domain.info", 10*time.Millisecond, false, false)
+
+	// a later run, whose process reuses the pid of the previous one
+	current := NewAPIMetrics()
+	current.process, current.run = "100", "2"
+	current.SetFile(path)
+	current.Record("domain.zone.info", 20*time.Millisecond, false, false)
+
+	report := readMetricsFile(t, path)
+	if report.Run != "2" || len(report.Processes) != 1 {
+		t.Fatalf("expected only the current run, got %#v", report)
+	}
+	if _, ok := report.Methods["domain.info"]; ok {
+		t.Fatalf("expected the calls of the previous run to be dropped: %#v", report.Methods)
+	}
+	if s := report.Methods["domain.zone.info"]; s.Calls != 1 {
+		t.Fatalf("unexpected metrics: %#v", s)
+	}
+}
+
+func TestGandiTransportMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testXMLRPCFault))
+	}))
+	defer server.Close()
+
+	before := apiMetrics.snapshotOf("domain.info")
+
+	transport := &gandiTransport{next: &http.Transport{}}
+	body := `<?xml version="1.0"?><methodCall><methodName>domain.info</methodName></methodCall>`
+	req, _ := http.NewRequest("POST", server.URL, strings.NewReader(body))
+	// faults are returned as errors, counted once
+	if _, err := transport.RoundTrip(req); !isNotFound(err) {
+		t.Fatalf("expected a not found fault, got: %v", err)
+	}
+
+	after := apiMetrics.snapshotOf("domain.info")
+	if after.Calls != before.Calls+1 || after.Faults != before.Faults+1 {
+		t.Fatalf("expected one more call and fault, got %#v then %#v", before, after)
+	}
+}
+
+func (m *APIMetrics) snapshotOf(method string) MethodMetrics {
+	m.Lock()
+	defer m.Unlock()
+	return m.snapshot()[method]
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/bemehow/terraform-provider-gandi/gandi/operation"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	// defaultOperationTimeout bounds the wait for asynchronous operations
+	defaultOperationTimeout = "10m"
+	// operationPollInterval is the delay between two operation.info calls
+	operationPollInterval = 5 * time.Second
+)
+
+// Steps of Gandi operations, all but the pending ones are terminal
+const (
+	operationStepBill    = "BILL"
+	operationStepWait    = "WAIT"
+	operationStepRun     = "RUN"
+	operationStepDone    = "DONE"
+	operationStepError   = "ERROR"
+	operationStepCancel  = "CANCEL"
+	operationStepSupport = "SUPPORT"
+)
+
+// operationIDSchema is the computed attribute holding the ID of the last
+// operation a resource waited for
+func operationIDSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeString,
+		Computed: true,
+	}
+}
+
+// getOperationClient wraps Gandi Client in Operation Methods
+func getOperationClient(meta interface{}) *operation.Operation {
+	return operation.New(meta.(*Meta).Client)
+}
+
+func isPendingOperation(op *operation.OperationInfo) bool {
+	switch op.Step {
+	case operationStepBill, operationStepWait, operationStepRun:
+		return true
+	}
+	return false
+}
+
+// waitForOperation polls op with info until it reaches a terminal step and
+// fails unless it is done, or when ctx is done first
+func waitForOperation(ctx context.Context, info func(int64) (*operation.OperationInfo, error), op *operation.OperationInfo,
+	timeout time.Duration, interval time.Duration) (*operation.OperationInfo, error) {
+	deadline := time.Now().Add(timeout)
+
+	for isPendingOperation(op) {
+		if time.Now().After(deadline) {
+			return op, fmt.Errorf("Operation %d (%s) did not complete within %s, last step: %s",
+				op.Id, op.Type, timeout, op.Step)
+		}
+
+		log.Printf("[DEBUG] Waiting for operation %d (%s), step: %s", op.Id, op.Type, op.Step)
+		select {
+		case <-ctx.Done():
+			return op, fmt.Errorf("Stopped waiting for operation %d (%s), last step: %s: %v",
+				op.Id, op.Type, op.Step, ctx.Err())
+		case <-time.After(interval):
+		}
+
+		current, err := info(op.Id)
+		if err != nil {
+			return op, wrapAPIError(err, "Cannot read operation %d", op.Id)
+		}
+		op = current
+	}
+
+	switch op.Step {
+	case operationStepDone:
+		log.Printf("[DEBUG] Operation %d (%s) done", op.Id, op.Type)
+		return op, nil
+	case operationStepError:
+		return op, fmt.Errorf("Operation %d (%s) failed: %s", op.Id, op.Type, op.LastError)
+	case operationStepSupport:
+		return op, fmt.Errorf("Operation %d (%s) needs Gandi support: %s", op.Id, op.Type, op.LastError)
+	default:
+		return op, fmt.Errorf("Operation %d (%s) ended with step %s: %s", op.Id, op.Type, op.Step, op.LastError)
+	}
+}
+
+// WaitForOperation waits for an operation within the configured timeout
+// and records its ID in the operation_id attribute
+func WaitForOperation(ctx context.Context, d *schema.ResourceData, meta interface{}, op *operation.OperationInfo) error {
+	d.Set("operation_id", strconv.FormatInt(op.Id, 10))
+
+	_, err := waitForOperation(ctx, getOperationClient(meta).Info, op, meta.(*Meta).OperationTimeout, operationPollInterval)
+	return err
+}
+
+// toOperationInfo decodes the operation returned by asynchronous calls that
+// the gandi client does not wrap
+func toOperationInfo(res map[string]interface{}) *operation.OperationInfo {
+	op := &operation.OperationInfo{}
+	op.Id, _ = res["id"].(int64)
+	op.SourceId, _ = res["source_id"].(int64)
+	op.Step, _ = res["step"].(string)
+	op.Type, _ = res["type"].(string)
+	op.LastError, _ = res["last_error"].(string)
+	return op
+}
+
+// callOperation runs an asynchronous method and returns its operation
+func callOperation(meta interface{}, method string, params ...interface{}) (*operation.OperationInfo, error) {
+	c := meta.(*Meta).Client
+
+	var res map[string]interface{}
+	if err := c.Call(method, append([]interface{}{c.Key}, params...), &res); err != nil {
+		return nil, err
+	}
+	return toOperationInfo(res), nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bemehow/terraform-provider-gandi/gandi/operation"
+)
+
+// testOperationInfo replays the given steps of operation 42, one per call
+func testOperationInfo(steps ...string) func(int64) (*operation.OperationInfo, error) {
+	return func(id int64) (*operation.OperationInfo, error) {
+		if id != 42 {
+			return nil, fmt.Errorf("unexpected operation: %d", id)
+		}
+		op := &operation.OperationInfo{Id: id, Type: "domain_update", Step: steps[0]}
+		if op.Step == operationStepError {
+			op.LastError = "Invalid nameserver"
+		}
+		if len(steps) > 1 {
+			steps = steps[1:]
+		}
+		return op, nil
+	}
+}
+
+func TestWaitForOperation(t *testing.T) {
+	op := &operation.OperationInfo{Id: 42, Type: "domain_update", Step: operationStepBill}
+	info := testOperationInfo(operationStepWait, operationStepRun, operationStepDone)
+
+	done, err := waitForOperation(context.Background(), info, op, time.Second, time.Millisecond)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if done.Step != operationStepDone {
+		t.Fatalf("expected the operation to be done, got: %s", done.Step)
+	}
+}
+
+func TestWaitForOperationAlreadyDone(t *testing.T) {
+	op := &operation.OperationInfo{Id: 42, Step: operationStepDone}
+	info := func(int64) (*operation.OperationInfo, error) {
+		return nil, fmt.Errorf("a finished operation must not be polled")
+	}
+
+	if _, err := waitForOperation(context.Background(), info, op, time.Second, time.Millisecond); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestWaitForOperationErrors(t *testing.T) {
+	cases := map[string][]string{
+		"failed: Invalid nameserver": []string{operationStepRun, operationStepError},
+		"needs Gandi support":        []string{operationStepSupport},
+		"ended with step CANCEL":     []string{operationStepCancel},
+		"did not complete within":    []string{operationStepRun},
+	}
+
+	for message, steps := range cases {
+		op := &operation.OperationInfo{Id: 42, Type: "domain_update", Step: operationStepWait}
+		_, err := waitForOperation(context.Background(), testOperationInfo(steps...), op, 50*time.Millisecond, time.Millisecond)
+		if err == nil || !strings.Contains(err.Error(), message) {
+			t.Fatalf("expected error containing %q, got: %v", message, err)
+		}
+	}
+}
+
+func TestWaitForOperationCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	op := &operation.OperationInfo{Id: 42, Type: "domain_update", Step: operationStepRun}
+	_, err := waitForOperation(ctx, testOperationInfo(operationStepRun), op, time.Second, time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "Stopped waiting for operation 42") {
+		t.Fatalf("expected the wait to stop with the context, got: %v", err)
+	}
+}
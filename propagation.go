@@ -0,0 +1,243 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const (
+	defaultPropagationTimeout  = "5m"
+	defaultPropagationInterval = "10s"
+)
+
+func propagationSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"domain": &schema.Schema{
+					Type:        schema.TypeString,
+					Required:    true,
+					Description: "Domain served by the zone, the record name is relative to it.",
+				},
+				"nameservers": &schema.Schema{
+					Type:     schema.TypeList,
+					Required: true,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+				},
+				"timeout": &schema.Schema{
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      defaultPropagationTimeout,
+					ValidateFunc: validateDuration,
+				},
+				"interval": &schema.Schema{
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      defaultPropagationInterval,
+					ValidateFunc: validateDuration,
+				},
+			},
+		},
+	}
+}
+
+func validateDuration(v interface{}, k string) (ws []string, es []error) {
+	if _, err := time.ParseDuration(v.(string)); err != nil {
+		es = append(es, fmt.Errorf("%q must be a duration such as 30s or 5m: %v", k, err))
+	}
+	return
+}
+
+// Propagation describes how to wait for nameservers to serve a record
+type Propagation struct {
+	Domain      string
+	Nameservers []string
+	Timeout     time.Duration
+	Interval    time.Duration
+}
+
+// getPropagation reads the wait_for_propagation block, nil when not set
+func getPropagation(d *schema.ResourceData) *Propagation {
+	blocks := d.Get("wait_for_propagation").([]interface{})
+	if len(blocks) == 0 {
+		return nil
+	}
+
+	block := blocks[0].(map[string]interface{})
+	p := &Propagation{Domain: block["domain"].(string)}
+	for _, ns := range block["nameservers"].([]interface{}) {
+		p.Nameservers = append(p.Nameservers, ns.(string))
+	}
+	// Durations were checked by validateDuration
+	p.Timeout, _ = time.ParseDuration(block["timeout"].(string))
+	p.Interval, _ = time.ParseDuration(block["interval"].(string))
+
+	return p
+}
+
+// recordFQDN returns the absolute name of a record of the zone serving domain
+func recordFQDN(name string, domain string) string {
+	domain = strings.TrimSuffix(domain, ".") + "."
+	if isZoneApex(name) {
+		return domain
+	}
+	if strings.HasSuffix(name, ".") {
+		return name
+	}
+	return name + "." + domain
+}
+
+// nameserverResolver sends every query to the given nameserver
+func nameserverResolver(nameserver string) *net.Resolver {
+	if _, _, err := net.SplitHostPort(nameserver); err != nil {
+		nameserver = net.JoinHostPort(nameserver, "53")
+	}
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, network, nameserver)
+		},
+	}
+}
+
+// sameHost compares host names, resolving relative ones against domain
+func sameHost(a string, b string, domain string) bool {
+	return strings.EqualFold(recordFQDN(a, domain), recordFQDN(b, domain))
+}
+
+// servesRecord reports whether the resolver answers with the record value
+func servesRecord(ctx context.Context, resolver *net.Resolver, zr *ZoneRecord, domain string) (bool, error) {
+	fqdn := recordFQDN(zr.Name, domain)
+	fields := strings.Fields(zr.Value)
+
+	switch strings.ToUpper(zr.Type) {
+	case "A", "AAAA":
+		expected := net.ParseIP(zr.Value)
+		addrs, err := resolver.LookupIPAddr(ctx, fqdn)
+		if err != nil {
+			return false, err
+		}
+		for _, addr := range addrs {
+			if addr.IP.Equal(expected) {
+				return true, nil
+			}
+		}
+	case "CNAME":
+		cname, err := resolver.LookupCNAME(ctx, fqdn)
+		if err != nil {
+			return false, err
+		}
+		return sameHost(cname, zr.Value, domain), nil
+	case "TXT":
+		expected := strings.Join(txtStrings(zr.Value), "")
+		txts, err := resolver.LookupTXT(ctx, fqdn)
+		if err != nil {
+			return false, err
+		}
+		for _, txt := range txts {
+			if txt == expected {
+				return true, nil
+			}
+		}
+	case "MX":
+		if len(fields) != 2 {
+			return false, fmt.Errorf("Invalid MX value: %s", zr.Value)
+		}
+		mxs, err := resolver.LookupMX(ctx, fqdn)
+		if err != nil {
+			return false, err
+		}
+		for _, mx := range mxs {
+			if strconv.Itoa(int(mx.Pref)) == fields[0] && sameHost(mx.Host, fields[1], domain) {
+				return true, nil
+			}
+		}
+	case "NS":
+		nss, err := resolver.LookupNS(ctx, fqdn)
+		if err != nil {
+			return false, err
+		}
+		for _, ns := range nss {
+			if sameHost(ns.Host, zr.Value, domain) {
+				return true, nil
+			}
+		}
+	case "SRV":
+		if len(fields) != 4 {
+			return false, fmt.Errorf("Invalid SRV value: %s", zr.Value)
+		}
+		_, srvs, err := resolver.LookupSRV(ctx, "", "", fqdn)
+		if err != nil {
+			return false, err
+		}
+		for _, srv := range srvs {
+			value := fmt.Sprintf("%d %d %d", srv.Priority, srv.Weight, srv.Port)
+			if value == strings.Join(fields[:3], " ") && sameHost(srv.Target, fields[3], domain) {
+				return true, nil
+			}
+		}
+	default:
+		return false, fmt.Errorf("Cannot check propagation of %s records", zr.Type)
+	}
+
+	return false, nil
+}
+
+// WaitForPropagation polls the nameservers until all of them serve the
+// record, within the propagation timeout and the lifetime of ctx
+func WaitForPropagation(parent context.Context, zr *ZoneRecord, p *Propagation) error {
+	ctx, cancel := context.WithTimeout(parent, p.Timeout)
+	defer cancel()
+
+	pending := make(map[string]error)
+	for _, ns := range p.Nameservers {
+		pending[ns] = nil
+	}
+
+	for {
+		for ns := range pending {
+			ok, err := servesRecord(ctx, nameserverResolver(ns), zr, p.Domain)
+			if ok {
+				log.Printf("[DEBUG] Record %s %s served by %s", zr.Name, zr.Type, ns)
+				delete(pending, ns)
+				continue
+			}
+			pending[ns] = err
+		}
+
+		if len(pending) == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			var waiting []string
+			for ns, err := range pending {
+				if err != nil {
+					waiting = append(waiting, fmt.Sprintf("%s (%v)", ns, err))
+				} else {
+					waiting = append(waiting, ns)
+				}
+			}
+			sort.Strings(waiting)
+			if err := parent.Err(); err != nil {
+				return fmt.Errorf("Stopped waiting for record %s %s %s to be served by: %s: %v",
+					recordFQDN(zr.Name, p.Domain), zr.Type, zr.Value, strings.Join(waiting, ", "), err)
+			}
+			return fmt.Errorf("Record %s %s %s not served after %s by: %s",
+				recordFQDN(zr.Name, p.Domain), zr.Type, zr.Value, p.Timeout, strings.Join(waiting, ", "))
+		case <-time.After(p.Interval):
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bemehow/terraform-provider-gandi/gandi/domain/zone/record"
+)
+
+const (
+	dnsTypeA   = 1
+	dnsTypeTXT = 16
+)
+
+// stubNameserver is a minimal authoritative DNS server answering A and TXT
+// queries from a table that tests can change while it is running
+type stubNameserver struct {
+	conn net.PacketConn
+
+	sync.Mutex
+	answers map[string][][]byte
+}
+
+func newStubNameserver(t *testing.T) *stubNameserver {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	s := &stubNameserver{conn: conn, answers: make(map[string][][]byte)}
+	go s.serve()
+	t.Cleanup(func() { conn.Close() })
+
+	return s
+}
+
+func (s *stubNameserver) Addr() string {
+	return s.conn.LocalAddr().String()
+}
+
+func stubKey(name string, qtype uint16) string {
+	return fmt.Sprintf("%s/%d", strings.ToLower(name), qtype)
+}
+
+func (s *stubNameserver) AddA(name string, ip string) {
+	s.Lock()
+	defer s.Unlock()
+	key := stubKey(name, dnsTypeA)
+	s.answers[key] = append(s.answers[key], net.ParseIP(ip).To4())
+}
+
+func (s *stubNameserver) AddTXT(name string, txt string) {
+	s.Lock()
+	defer s.Unlock()
+	key := stubKey(name, dnsTypeTXT)
+	s.answers[key] = append(s.answers[key], append([]byte{byte(len(txt))}, txt...))
+}
+
+func (s *stubNameserver) serve() {
+	buf := make([]byte, 512)
+	for {
+		n, addr, err := s.conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		if resp := s.answer(buf[:n]); resp != nil {
+			s.conn.WriteTo(resp, addr)
+		}
+	}
+}
+
+// answer builds the response to a query, echoing its single question
+func (s *stubNameserver) answer(query []byte) []byte {
+	if len(query) < 12 {
+		return nil
+	}
+
+	var labels []string
+	i := 12
+	for i < len(query) && query[i] != 0 {
+		l := int(query[i])
+		if i+1+l > len(query) {
+			return nil
+		}
+		labels = append(labels, string(query[i+1:i+1+l]))
+		i += 1 + l
+	}
+	if i+5 > len(query) {
+		return nil
+	}
+	question := query[12 : i+5]
+	qtype := binary.BigEndian.Uint16(query[i+1 : i+3])
+
+	s.Lock()
+	rdatas := s.answers[stubKey(strings.Join(labels, ".")+".", qtype)]
+	s.Unlock()
+
+	resp := make([]byte, 12, 512)
+	copy(resp, query[:2])
+	binary.BigEndian.PutUint16(resp[2:], 0x8400) // response, authoritative
+	binary.BigEndian.PutUint16(resp[4:], 1)
+	binary.BigEndian.PutUint16(resp[6:], uint16(len(rdatas)))
+	resp = append(resp, question...)
+
+	for _, rdata := range rdatas {
+		rr := make([]byte, 12)
+		binary.BigEndian.PutUint16(rr[0:], 0xc00c) // pointer to the question name
+		binary.BigEndian.PutUint16(rr[2:], qtype)
+		binary.BigEndian.PutUint16(rr[4:], 1) // IN
+		binary.BigEndian.PutUint32(rr[6:], 300)
+		binary.BigEndian.PutUint16(rr[10:], uint16(len(rdata)))
+		resp = append(resp, rr...)
+		resp = append(resp, rdata...)
+	}
+
+	return resp
+}
+
+func TestWaitForPropagation(t *testing.T) {
+	ns1 := newStubNameserver(t)
+	ns2 := newStubNameserver(t)
+	ns1.AddA("www.example.com.", "1.1.1.1")
+
+	// the second nameserver only picks up the record after a while
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		ns2.AddA("www.example.com.", "1.1.1.1")
+	}()
+
+	zr := &ZoneRecord{RecordInfo: record.RecordInfo{Name: "www", Type: "A", Value: "1.1.1.1"}}
+	p := &Propagation{
+		Domain:      "example.com",
+		Nameservers: []string{ns1.Addr(), ns2.Addr()},
+		Timeout:     5 * time.Second,
+		Interval:    20 * time.Millisecond,
+	}
+
+	if err := WaitForPropagation(context.Background(), zr, p); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestWaitForPropagationTXT(t *testing.T) {
+	ns := newStubNameserver(t)
+	ns.AddTXT("_acme-challenge.example.com.", "other")
+	ns.AddTXT("_acme-challenge.example.com.", "token-value")
+
+	zr := &ZoneRecord{RecordInfo: record.RecordInfo{Name: "_acme-challenge", Type: "TXT", Value: `"token-value"`}}
+	p := &Propagation{
+		Domain:      "example.com.",
+		Nameservers: []string{ns.Addr()},
+		Timeout:     5 * time.Second,
+		Interval:    20 * time.Millisecond,
+	}
+
+	if err := WaitForPropagation(context.Background(), zr, p); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestWaitForPropagationTimeout(t *testing.T) {
+	ns := newStubNameserver(t)
+	ns.AddA("www.example.com.", "2.2.2.2")
+
+	zr := &ZoneRecord{RecordInfo: record.RecordInfo{Name: "www", Type: "A", Value: "1.1.1.1"}}
+	p := &Propagation{
+		Domain:      "example.com",
+		Nameservers: []string{ns.Addr()},
+		Timeout:     200 * time.Millisecond,
+		Interval:    20 * time.Millisecond,
+	}
+
+	err := WaitForPropagation(context.Background(), zr, p)
+	if err == nil || !strings.Contains(err.Error(), "not served after 200ms by: "+ns.Addr()) {
+		t.Fatalf("expected a timeout naming the nameserver, got: %v", err)
+	}
+}
+
+func TestWaitForPropagationResourceTimeout(t *testing.T) {
+	ns := newStubNameserver(t)
+	ns.AddA("www.example.com.", "2.2.2.2")
+
+	zr := &ZoneRecord{RecordInfo: record.RecordInfo{Name: "www", Type: "A", Value: "1.1.1.1"}}
+	p := &Propagation{
+		Domain:      "example.com",
+		Nameservers: []string{ns.Addr()},
+		Timeout:     time.Minute,
+		Interval:    20 * time.Millisecond,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := WaitForPropagation(ctx, zr, p)
+	if err == nil || !strings.Contains(err.Error(), "Stopped waiting for record www.example.com. A 1.1.1.1") {
+		t.Fatalf("expected the resource timeout to stop the wait, got: %v", err)
+	}
+	if time.Since(start) > 10*time.Second {
+		t.Fatalf("expected the wait to stop with ctx, took %s", time.Since(start))
+	}
+}
+
+func TestRecordFQDN(t *testing.T) {
+	cases := map[string]string{
+		"@":             "example.com.",
+		"":              "example.com.",
+		"www":           "www.example.com.",
+		"mx.other.net.": "mx.other.net.",
+	}
+
+	for name, expected := range cases {
+		if fqdn := recordFQDN(name, "example.com"); fqdn != expected {
+			t.Fatalf("recordFQDN(%q) = %q, want %q", name, fqdn, expected)
+		}
+	}
+}
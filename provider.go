@@ -1,19 +1,36 @@
 package main
 
 import (
-	"github.com/hashicorp/terraform/helper/schema"
-	"github.com/hashicorp/terraform/terraform"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 // Provider returns Gandi Resoruce Provider...
-func Provider() terraform.ResourceProvider {
-	return &schema.Provider{
+func Provider() *schema.Provider {
+	provider := &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"key": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"key_file", "profile"},
+				Sensitive:     true,
+				Description:   "A Gandi XMLRPC API Key, defaults to GANDI_KEY.",
+			},
+			"key_file": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"key", "profile"},
+				Description:   "File holding the API Key, defaults to GANDI_KEY_FILE.",
+			},
+			"profile": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"key", "key_file"},
+				Description:   "Account of the credentials file to use, defaults to GANDI_PROFILE.",
+			},
+			"credentials_file": &schema.Schema{
 				Type:        schema.TypeString,
-				Required:    true,
-				DefaultFunc: schema.EnvDefaultFunc("GANDI_KEY", nil),
-				Description: "A Gandi XMLRPC API Key.",
+				Optional:    true,
+				Description: "INI file of named accounts, defaults to GANDI_CREDENTIALS_FILE or " + DefaultCredentialsFile + ".",
 			},
 			"testing": &schema.Schema{
 				Type:        schema.TypeBool,
@@ -21,14 +38,73 @@ func Provider() terraform.ResourceProvider {
 				DefaultFunc: schema.EnvDefaultFunc("GANDI_TESTING", nil),
 				Description: "Set it to use the Test Environment.",
 			},
+			"operation_timeout": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      defaultOperationTimeout,
+				ValidateFunc: validateDuration,
+				Description:  "How long to wait for asynchronous Gandi operations.",
+			},
+			"api_timeout": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      defaultAPITimeout,
+				ValidateFunc: validateDuration,
+				Description:  "How long a single API call may take before failing, 0 to only use the resource timeouts.",
+			},
+			"metrics_file": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("GANDI_METRICS_FILE", ""),
+				Description: "JSON file receiving the count, faults and latency of the API calls per method, merged across the provider processes of a Terraform command. The counts of earlier commands are replaced.",
+			},
+			"trace": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("GANDI_TRACE", false),
+				Description: "Log every API call with its parameters, result and duration at DEBUG level.",
+			},
+			"trace_file": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("GANDI_TRACE_FILE", ""),
+				Description: "File the API calls are appended to as JSON lines, enables trace.",
+			},
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
 			"gandi_zone":         resourceZone(),
 			"gandi_record":       resourceRecord(),
 			"gandi_zone_version": resourceZoneVersion(),
+
+			"gandi_domain":             resourceDomain(),
+			"gandi_domain_nameservers": resourceDomainNameservers(),
+			"gandi_domain_host":        resourceDomainHost(),
+			"gandi_domain_settings":    resourceDomainSettings(),
+			"gandi_dnssec_key":         resourceDNSSECKey(),
+			"gandi_mail_forward":       resourceMailForward(),
+			"gandi_mailbox":            resourceMailbox(),
+			"gandi_web_redirection":    resourceWebRedirection(),
+			"gandi_contact":            resourceContact(),
+		},
+
+		DataSourcesMap: map[string]*schema.Resource{
+			"gandi_zone_version_diff":   dataSourceZoneVersionDiff(),
+			"gandi_zone_file":           dataSourceZoneFile(),
+			"gandi_account":             dataSourceAccount(),
+			"gandi_domain":              dataSourceDomain(),
+			"gandi_domain_availability": dataSourceDomainAvailability(),
 		},
 
-		ConfigureFunc: providerConfigure,
+		ConfigureContextFunc: providerConfigure,
 	}
+
+	for name, r := range provider.ResourcesMap {
+		withAccount(name, r)
+	}
+	for name, r := range provider.DataSourcesMap {
+		withAccount("data."+name, r)
+	}
+
+	return provider
 }
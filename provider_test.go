@@ -4,14 +4,13 @@ import (
 	"os"
 	"testing"
 
-	"github.com/hashicorp/terraform/helper/schema"
-	"github.com/hashicorp/terraform/terraform"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 // Acceptance Tests for the Gandi Provider
 
 func TestProvider(t *testing.T) {
-	if err := Provider().(*schema.Provider).InternalValidate(); err != nil {
+	if err := Provider().InternalValidate(); err != nil {
 		t.Fatalf("err: %s", err)
 	}
 }
@@ -20,22 +19,18 @@ func TestProvider(t *testing.T) {
 // There is a need to set-up the access credentials and enable API access
 //
 // With all of that done, you can run like this:
-//    make testacc TEST=./builtin/providers/gandi
+//    TF_ACC=1 go test -run TestAcc ./...
 
-var testAccProviders map[string]terraform.ResourceProvider
+var testAccProviderFactories map[string]func() (*schema.Provider, error)
 var testAccProvider *schema.Provider
 
 func init() {
-	testAccProvider = Provider().(*schema.Provider)
-	testAccProviders = map[string]terraform.ResourceProvider{
-		"gandi": testAccProvider,
+	testAccProvider = Provider()
+	testAccProviderFactories = map[string]func() (*schema.Provider, error){
+		"gandi": func() (*schema.Provider, error) { return testAccProvider, nil },
 	}
 }
 
-func TestProvider_impl(t *testing.T) {
-	var _ terraform.ResourceProvider = Provider()
-}
-
 func testAccPreCheck(t *testing.T) {
 	if v := os.Getenv("GANDI_KEY"); v == "" {
 		t.Fatal("GANDI_KEY must be set for acceptance tests")
@@ -71,3 +66,10 @@ func testAccPreCheckZoneVersion(t *testing.T) {
 		t.Fatal("GANDI_ZONE_ID must be set for acceptance tests")
 	}
 }
+
+func testAccPreCheckDomain(t *testing.T) {
+	// a domain registered in the test account
+	if v := os.Getenv("GANDI_DOMAIN"); v == "" {
+		t.Fatal("GANDI_DOMAIN must be set for acceptance tests")
+	}
+}
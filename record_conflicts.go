@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/bemehow/terraform-provider-gandi/gandi/domain/zone/record"
+)
+
+// isZoneApex reports whether a record name designates the zone apex
+func isZoneApex(name string) bool {
+	return name == "" || name == "@"
+}
+
+// FindRecordConflict returns why a record cannot be added next to the
+// records already in a zone version, the record with id is skipped so a
+// record can be checked against the version it is being updated in
+func FindRecordConflict(records []*record.RecordInfo, id int64, name string, recordType string, value string) error {
+	recordType = strings.ToUpper(recordType)
+	if recordType == "CNAME" && isZoneApex(name) {
+		return fmt.Errorf("CNAME records are not allowed at the zone apex")
+	}
+
+	for _, r := range records {
+		if r.Id == id || !strings.EqualFold(r.Name, name) {
+			continue
+		}
+		existingType := strings.ToUpper(r.Type)
+
+		// Terraform orders neither the checks nor the changes of different
+		// resources, replacing a record by a conflicting one takes two applies
+		switch {
+		case existingType == recordType && unquoteRecordValue(r.Value) == unquoteRecordValue(value):
+			return fmt.Errorf("Duplicate record: %s %s %s already exists with ID: %d, "+
+				"a record replacing it must be added in a later apply", name, recordType, value, r.Id)
+		case recordType == "CNAME":
+			return fmt.Errorf("CNAME record %s conflicts with %s record %d at the same name, "+
+				"it must be removed in an earlier apply", name, existingType, r.Id)
+		case existingType == "CNAME":
+			return fmt.Errorf("%s record %s conflicts with CNAME record %d at the same name, "+
+				"it must be removed in an earlier apply", recordType, name, r.Id)
+		}
+	}
+
+	return nil
+}
+
+// managedRecords holds the IDs of the records read or written by gandi_record
+// resources in this process
+var managedRecords sync.Map
+
+// markManagedRecord notes that a gandi_record resource manages the record
+func markManagedRecord(id int64) {
+	managedRecords.Store(id, true)
+}
+
+// unmanagedRecords drops the records managed by gandi_record resources
+func unmanagedRecords(records []*record.RecordInfo) []*record.RecordInfo {
+	var result []*record.RecordInfo
+	for _, r := range records {
+		if _, ok := managedRecords.Load(r.Id); !ok {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+// checkRecordConflicts makes sure the record can be written to its zone
+// version, the active one when none is set, before anything is changed.
+// During plan the records managed by gandi_record resources are skipped,
+// the same plan may remove or change them, apply checks them all.
+func checkRecordConflicts(meta interface{}, zr *ZoneRecord, planning bool) error {
+	version := zr.Version
+	if version == 0 {
+		var err error
+		_, version, err = getActiveZoneVersion(meta, zr.Zone)
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Printf("[DEBUG] Checking record conflicts in zone: %v version: %v", zr.Zone, version)
+	records, err := listRecords(meta, zr.Zone, version)
+	if err != nil {
+		return wrapAPIError(err, "Cannot read records from zone: %v version: %v", zr.Zone, version)
+	}
+
+	if planning {
+		records = unmanagedRecords(records)
+	}
+	return FindRecordConflict(records, zr.Id, zr.Name, zr.Type, zr.Value)
+}
+
+// zoneLocks holds a lock per zone, taken by record changes from their
+// conflict check until the zone version they wrote is active
+var zoneLocks = struct {
+	sync.Mutex
+	zones map[int64]chan struct{}
+}{zones: make(map[int64]chan struct{})}
+
+// lockZone waits for the lock of the zone, or until ctx is done. Without it
+// records created in parallel each copy the active version and pass the
+// conflict check against it. The returned func releases the lock and may be
+// called more than once.
+func lockZone(ctx context.Context, zoneID int64) (func(), error) {
+	zoneLocks.Lock()
+	lock, ok := zoneLocks.zones[zoneID]
+	if !ok {
+		lock = make(chan struct{}, 1)
+		zoneLocks.zones[zoneID] = lock
+	}
+	zoneLocks.Unlock()
+
+	select {
+	case lock <- struct{}{}:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("Stopped waiting for changes to zone %d: %v", zoneID, ctx.Err())
+	}
+
+	var once sync.Once
+	return func() { once.Do(func() { <-lock }) }, nil
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bemehow/terraform-provider-gandi/gandi/domain/zone/record"
+)
+
+func TestFindRecordConflict(t *testing.T) {
+	records := []*record.RecordInfo{
+		{Id: 1, Name: "www", Type: "A", Value: "1.1.1.1", Ttl: 3600},
+		{Id: 2, Name: "mail", Type: "MX", Value: "10 mx.example.com.", Ttl: 3600},
+		{Id: 3, Name: "alias", Type: "CNAME", Value: "www", Ttl: 3600},
+		{Id: 4, Name: "txt", Type: "TXT", Value: `"foo"`, Ttl: 3600},
+	}
+
+	cases := []struct {
+		id               int64
+		name, typ, value string
+		conflict         string
+	}{
+		{0, "@", "CNAME", "www", "zone apex"},
+		{0, "", "cname", "www", "zone apex"},
+		{0, "www", "A", "1.1.1.1", "Duplicate record"},
+		{0, "WWW", "a", "1.1.1.1", "Duplicate record"},
+		{0, "txt", "TXT", "foo", "Duplicate record"},
+		{0, "www", "CNAME", "other", "conflicts with A record 1"},
+		{0, "alias", "A", "2.2.2.2", "conflicts with CNAME record 3"},
+		{0, "alias", "CNAME", "other", "conflicts with CNAME record 3"},
+		{0, "www", "A", "2.2.2.2", ""},
+		{0, "www", "AAAA", "fe80::1", ""},
+		{0, "new", "CNAME", "www", ""},
+		// updating a record in place does not conflict with itself
+		{1, "www", "A", "1.1.1.1", ""},
+		{3, "alias", "CNAME", "other", ""},
+		{1, "www", "CNAME", "other", ""},
+	}
+
+	for _, c := range cases {
+		err := FindRecordConflict(records, c.id, c.name, c.typ, c.value)
+		switch {
+		case c.conflict == "" && err != nil:
+			t.Fatalf("%s %s %s: unexpected conflict: %s", c.name, c.typ, c.value, err)
+		case c.conflict != "" && (err == nil || !strings.Contains(err.Error(), c.conflict)):
+			t.Fatalf("%s %s %s: expected conflict %q, got: %v", c.name, c.typ, c.value, c.conflict, err)
+		}
+	}
+}
+
+func TestCheckRecordConflictsPlanning(t *testing.T) {
+	var calls []string
+	meta := testGandiMethods(t, map[string]string{
+		"domain.zone.record.list": testXMLRPCResponse(`<array><data>` +
+			`<value><struct>` +
+			`<member><name>id</name><value><int>9001</int></value></member>` +
+			`<member><name>name</name><value><string>www</string></value></member>` +
+			`<member><name>type</name><value><string>A</string></value></member>` +
+			`<member><name>value</name><value><string>192.0.2.1</string></value></member>` +
+			`</struct></value>` +
+			`<value><struct>` +
+			`<member><name>id</name><value><int>9002</int></value></member>` +
+			`<member><name>name</name><value><string>mail</string></value></member>` +
+			`<member><name>type</name><value><string>A</string></value></member>` +
+			`<member><name>value</name><value><string>192.0.2.2</string></value></member>` +
+			`</struct></value>` +
+			`</data></array>`),
+	}, &calls)
+
+	// www is managed by a gandi_record the same plan may remove
+	markManagedRecord(9001)
+
+	www := &ZoneRecord{}
+	www.Zone, www.Version, www.Name, www.Type, www.Value = 42, 3, "www", "CNAME", "example.org."
+	if err := checkRecordConflicts(meta, www, true); err != nil {
+		t.Fatalf("expected managed records to be skipped during plan, got: %s", err)
+	}
+	err := checkRecordConflicts(meta, www, false)
+	if err == nil || !strings.Contains(err.Error(), "must be removed in an earlier apply") {
+		t.Fatalf("expected the conflict to be reported at apply, got: %v", err)
+	}
+
+	mail := &ZoneRecord{}
+	mail.Zone, mail.Version, mail.Name, mail.Type, mail.Value = 42, 3, "mail", "CNAME", "example.org."
+	if err := checkRecordConflicts(meta, mail, true); err == nil || !strings.Contains(err.Error(), "record 9002") {
+		t.Fatalf("expected unmanaged records to be checked during plan, got: %v", err)
+	}
+}
+
+func TestLockZone(t *testing.T) {
+	unlock, err := lockZone(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// another zone is not held by the lock
+	other, err := lockZone(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	other()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := lockZone(ctx, 1); err == nil || !strings.Contains(err.Error(), "Stopped waiting for changes to zone 1") {
+		t.Fatalf("expected the zone to stay locked, got: %v", err)
+	}
+
+	unlock()
+	unlock()
+	again, err := lockZone(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	again()
+}
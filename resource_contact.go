@@ -0,0 +1,238 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// contactTypes are the contact types in the order of their Gandi codes
+var contactTypes = []string{"person", "company", "association", "public_body", "reseller"}
+
+// contactFields maps the attributes of gandi_contact to the Gandi fields
+var contactFields = map[string]string{
+	"given_name":     "given",
+	"family_name":    "family",
+	"organization":   "orgname",
+	"street_address": "streetaddr",
+	"zip":            "zip",
+	"city":           "city",
+	"state":          "state",
+	"country":        "country",
+	"email":          "email",
+	"phone":          "phone",
+}
+
+func resourceContact() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateContact,
+		UpdateContext: UpdateContact,
+		ReadContext:   ReadContact,
+		DeleteContext: DeleteContact,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"type": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "person",
+				ValidateFunc: validateContactType,
+			},
+			"given_name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"family_name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"organization": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Required for every type but person.",
+			},
+			"street_address": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"zip": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"city": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"state": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"country": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "ISO 3166 country code, e.g. FR.",
+			},
+			"email": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"phone": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "International format, e.g. +33.123456789.",
+			},
+			"password": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Sensitive:   true,
+				Description: "Password of the contact account, cannot be read back.",
+			},
+			"hide_data": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Hide the contact details in the WHOIS.",
+			},
+			"hide_email": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Hide the email address in the WHOIS.",
+			},
+			"handle": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func validateContactType(v interface{}, k string) (ws []string, es []error) {
+	if contactTypeCode(v.(string)) < 0 {
+		es = append(es, fmt.Errorf("%q must be one of %v, got: %s", k, contactTypes, v))
+	}
+	return
+}
+
+// contactTypeCode returns the Gandi code of a contact type, -1 if unknown
+func contactTypeCode(contactType string) int {
+	for i, t := range contactTypes {
+		if t == contactType {
+			return i
+		}
+	}
+	return -1
+}
+
+// getContactParams builds the contact.create and contact.update parameters,
+// the type cannot be updated. Fields removed from the configuration are
+// sent empty so that Gandi clears them.
+func getContactParams(d *schema.ResourceData) map[string]interface{} {
+	password := d.Get("password").(string)
+	logSecrets.Register(password)
+
+	params := map[string]interface{}{
+		"password":        password,
+		"data_obfuscated": d.Get("hide_data").(bool),
+		"mail_obfuscated": d.Get("hide_email").(bool),
+	}
+	for attr, field := range contactFields {
+		if v := d.Get(attr).(string); v != "" || d.HasChange(attr) {
+			params[field] = v
+		}
+	}
+	return params
+}
+
+// CreateContact creates the contact and records its handle as ID
+func CreateContact(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*Meta).Client
+
+	params := getContactParams(d)
+	params["type"] = contactTypeCode(d.Get("type").(string))
+
+	log.Printf("[DEBUG] Creating contact: %s %s", d.Get("given_name"), d.Get("family_name"))
+	var res map[string]interface{}
+	if err := c.Call("contact.create", []interface{}{c.Key, params}, &res); err != nil {
+		return diag.FromErr(wrapAPIError(err, "Cannot create contact"))
+	}
+
+	handle, _ := res["handle"].(string)
+	if handle == "" {
+		return diag.Errorf("Cannot create contact: no handle returned")
+	}
+	d.SetId(handle)
+	log.Printf("[INFO] Created contact with handle: %v", d.Id())
+
+	return ReadContact(ctx, d, meta)
+}
+
+// ReadContact fetches the details of the contact, the password stays as
+// configured since Gandi never returns it
+func ReadContact(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*Meta).Client
+	log.Printf("[DEBUG] Reading contact: %v", d.Id())
+
+	var res map[string]interface{}
+	if err := c.Call("contact.info", []interface{}{c.Key, d.Id()}, &res); err != nil {
+		if isNotFound(err) {
+			log.Printf("[DEBUG] Contact %v not found. Cleaning local state reference", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(wrapAPIError(err, "Cannot read contact %s", d.Id()))
+	}
+
+	if code, ok := res["type"].(int64); ok && int(code) < len(contactTypes) {
+		d.Set("type", contactTypes[code])
+	}
+	for attr, field := range contactFields {
+		v, _ := res[field].(string)
+		d.Set(attr, v)
+	}
+	hideData, _ := res["data_obfuscated"].(bool)
+	hideEmail, _ := res["mail_obfuscated"].(bool)
+	d.Set("hide_data", hideData)
+	d.Set("hide_email", hideEmail)
+	d.Set("handle", d.Id())
+
+	return nil
+}
+
+// UpdateContact changes the details of the contact
+func UpdateContact(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*Meta).Client
+
+	// The password is never read back, keep the previous state when the
+	// update fails so that the next apply retries it
+	d.Partial(true)
+	log.Printf("[DEBUG] Updating contact: %v", d.Id())
+	var res map[string]interface{}
+	if err := c.Call("contact.update", []interface{}{c.Key, d.Id(), getContactParams(d)}, &res); err != nil {
+		return diag.FromErr(wrapAPIError(err, "Cannot update contact %s", d.Id()))
+	}
+	d.Partial(false)
+
+	return ReadContact(ctx, d, meta)
+}
+
+// DeleteContact deletes the contact, Gandi refuses it while the contact is
+// associated to a domain
+func DeleteContact(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*Meta).Client
+
+	log.Printf("[DEBUG] Deleting contact: %v", d.Id())
+	var res bool
+	if err := c.Call("contact.delete", []interface{}{c.Key, d.Id()}, &res); err != nil {
+		return diag.FromErr(wrapAPIError(err, "Cannot delete contact %s", d.Id()))
+	}
+
+	d.SetId("")
+	return nil
+}
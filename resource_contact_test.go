@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccGandiContact(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckGandiContactDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: fmt.Sprintf(testGandiContactConfig, "Paris"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"gandi_contact.test", "type", "person"),
+					resource.TestCheckResourceAttr(
+						"gandi_contact.test", "city", "Paris"),
+				),
+			},
+			resource.TestStep{
+				Config: fmt.Sprintf(testGandiContactConfig, "Lyon"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"gandi_contact.test", "city", "Lyon"),
+				),
+			},
+		},
+	})
+}
+
+func TestGetContactParams(t *testing.T) {
+	r := resourceContact()
+	state := &terraform.InstanceState{
+		ID: "TT1-GANDI",
+		Attributes: map[string]string{
+			"type":           "company",
+			"given_name":     "Terraform",
+			"family_name":    "Test",
+			"organization":   "Example",
+			"street_address": "63-65 boulevard Massena",
+			"zip":            "75013",
+			"city":           "Paris",
+			"state":          "IDF",
+			"country":        "FR",
+			"email":          "terraform@example.com",
+			"phone":          "+33.170377661",
+			"password":       "Terraform-Test-42",
+		},
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"type":           "company",
+		"given_name":     "Terraform",
+		"family_name":    "Test",
+		"organization":   "Example",
+		"street_address": "63-65 boulevard Massena",
+		"zip":            "75013",
+		"city":           "Paris",
+		"country":        "FR",
+		"email":          "terraform@example.com",
+		"phone":          "+33.170377661",
+		"password":       "Terraform-Test-42",
+	})
+
+	diff, err := r.Diff(context.Background(), state, config, nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	d, err := schema.InternalMap(r.Schema).Data(state, diff)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	params := getContactParams(d)
+	if v, ok := params["state"]; !ok || v != "" {
+		t.Fatalf("expected the removed state to be sent empty, got %#v", params)
+	}
+	if params["orgname"] != "Example" || params["city"] != "Paris" {
+		t.Fatalf("expected the configured fields, got %#v", params)
+	}
+}
+
+func TestUpdateContactFails(t *testing.T) {
+	meta := testGandiMeta(t, http.StatusOK, testXMLRPCFaultOf(500150,
+		"Error on object : OBJECT_CONTACT (CAUSE_BADPARAMETER) [password too weak]"))
+
+	r := resourceContact()
+	attributes := map[string]string{
+		"type":           "person",
+		"given_name":     "Terraform",
+		"family_name":    "Test",
+		"street_address": "63-65 boulevard Massena",
+		"zip":            "75013",
+		"city":           "Paris",
+		"country":        "FR",
+		"email":          "terraform@example.com",
+		"phone":          "+33.170377661",
+		"password":       "Terraform-Test-42",
+	}
+	config := make(map[string]interface{})
+	for k, v := range attributes {
+		config[k] = v
+	}
+	config["password"] = "Terraform-Test-43"
+
+	state := &terraform.InstanceState{ID: "TT1-GANDI", Attributes: attributes}
+	diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	d, err := schema.InternalMap(r.Schema).Data(state, diff)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if diags := UpdateContact(context.Background(), d, meta); !diags.HasError() {
+		t.Fatalf("expected the update to fail")
+	}
+	// the password is never read back, the next apply must retry it
+	if password := d.State().Attributes["password"]; password != "Terraform-Test-42" {
+		t.Fatalf("expected the previous password to be kept, got %s", password)
+	}
+}
+
+func testAccCheckGandiContactDestroy(s *terraform.State) error {
+	c := testAccProvider.Meta().(*Meta).Client
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "gandi_contact" {
+			continue
+		}
+
+		var res map[string]interface{}
+		if err := c.Call("contact.info", []interface{}{c.Key, rs.Primary.ID}, &res); err == nil {
+			return fmt.Errorf("Contact still exists")
+		}
+	}
+
+	return nil
+}
+
+const testGandiContactConfig = `
+resource "gandi_contact" "test" {
+  given_name = "Terraform"
+  family_name = "Test"
+  street_address = "63-65 boulevard Massena"
+  zip = "75013"
+  city = "%s"
+  country = "FR"
+  email = "terraform@example.com"
+  phone = "+33.170377661"
+  password = "Terraform-Test-42"
+}`
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// dsDigestTypeSHA256 is the DS digest type computed for the keys
+const dsDigestTypeSHA256 = 2
+
+func resourceDNSSECKey() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateDNSSECKey,
+		ReadContext:   ReadDNSSECKey,
+		DeleteContext: DeleteDNSSECKey,
+		Importer: &schema.ResourceImporter{
+			StateContext: importDNSSECKey,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"domain": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"algorithm": &schema.Schema{
+				Type:        schema.TypeInt,
+				Required:    true,
+				ForceNew:    true,
+				Description: "DNSSEC algorithm number, e.g. 8 for RSASHA256 or 13 for ECDSAP256SHA256.",
+			},
+			"flags": &schema.Schema{
+				Type:        schema.TypeInt,
+				Required:    true,
+				ForceNew:    true,
+				Description: "DNSKEY flags, 257 for a KSK or 256 for a ZSK.",
+			},
+			"public_key": &schema.Schema{
+				Type:      schema.TypeString,
+				Required:  true,
+				ForceNew:  true,
+				StateFunc: normalizePublicKey,
+			},
+			"keytag": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"digest": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"digest_type": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"operation_id": operationIDSchema(),
+		},
+	}
+}
+
+// normalizePublicKey drops the whitespace of a base64 public key, which is
+// often wrapped as in zone files
+func normalizePublicKey(v interface{}) string {
+	return strings.Join(strings.Fields(v.(string)), "")
+}
+
+// ComputeDS returns the key tag and SHA-256 DS digest of a DNSKEY as
+// specified by RFC 4034 and RFC 4509
+func ComputeDS(domain string, flags int, algorithm int, publicKey string) (int, string, error) {
+	key, err := base64.StdEncoding.DecodeString(normalizePublicKey(publicKey))
+	if err != nil {
+		return 0, "", fmt.Errorf("Invalid public key: %v", err)
+	}
+
+	// DNSKEY RDATA: flags, protocol (always 3), algorithm and key
+	rdata := append([]byte{byte(flags >> 8), byte(flags), 3, byte(algorithm)}, key...)
+
+	var ac uint32
+	for i, b := range rdata {
+		if i&1 == 1 {
+			ac += uint32(b)
+		} else {
+			ac += uint32(b) << 8
+		}
+	}
+	ac += ac >> 16 & 0xffff
+	keytag := int(ac & 0xffff)
+
+	var owner []byte
+	for _, label := range strings.Split(strings.ToLower(strings.TrimSuffix(domain, ".")), ".") {
+		owner = append(owner, byte(len(label)))
+		owner = append(owner, label...)
+	}
+	owner = append(owner, 0)
+
+	digest := sha256.Sum256(append(owner, rdata...))
+	return keytag, strings.ToUpper(hex.EncodeToString(digest[:])), nil
+}
+
+// listDNSSECKeys returns the keys published for a domain
+func listDNSSECKeys(meta interface{}, domain string) ([]map[string]interface{}, error) {
+	c := meta.(*Meta).Client
+
+	var res []map[string]interface{}
+	if err := c.Call("domain.dnssec.list", []interface{}{c.Key, domain}, &res); err != nil {
+		return nil, wrapAPIError(err, "Cannot list DNSSEC keys of %s", domain)
+	}
+	return res, nil
+}
+
+// importDNSSECKey splits the <domain>/<key_id> ID given to terraform
+// import, the key is then read from the keys published for the domain
+func importDNSSECKey(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.Split(d.Id(), "/")
+	if len(parts) != 2 || parts[0] == "" {
+		return nil, fmt.Errorf("Invalid DNSSEC key import ID %q, expected <domain>/<key_id>", d.Id())
+	}
+	if _, err := strconv.ParseInt(parts[1], 10, 64); err != nil {
+		return nil, fmt.Errorf("Invalid DNSSEC key import ID %q, expected <domain>/<key_id>", d.Id())
+	}
+
+	d.Set("domain", parts[0])
+	d.SetId(parts[1])
+	return []*schema.ResourceData{d}, nil
+}
+
+// CreateDNSSECKey publishes the key at the registry
+func CreateDNSSECKey(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	domain := d.Get("domain").(string)
+	publicKey := normalizePublicKey(d.Get("public_key"))
+
+	if _, _, err := ComputeDS(domain, d.Get("flags").(int), d.Get("algorithm").(int), publicKey); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[DEBUG] Creating DNSSEC key for domain: %v", domain)
+	op, err := callOperation(meta, "domain.dnssec.create", domain, map[string]interface{}{
+		"flags":      d.Get("flags").(int),
+		"algorithm":  d.Get("algorithm").(int),
+		"public_key": publicKey,
+	})
+	if err != nil {
+		return diag.FromErr(wrapAPIError(err, "Cannot create DNSSEC key for %s", domain))
+	}
+	if err := WaitForOperation(ctx, d, meta, op); err != nil {
+		return diag.FromErr(err)
+	}
+
+	keys, err := listDNSSECKeys(meta, domain)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	for _, key := range keys {
+		if k, _ := key["public_key"].(string); normalizePublicKey(k) == publicKey {
+			id, _ := key["id"].(int64)
+			d.SetId(strconv.FormatInt(id, 10))
+			log.Printf("[INFO] Created DNSSEC key with ID: %v", d.Id())
+			return ReadDNSSECKey(ctx, d, meta)
+		}
+	}
+
+	return diag.Errorf("DNSSEC key for %s not found after creation", domain)
+}
+
+// ReadDNSSECKey checks the key is still published and computes its DS
+func ReadDNSSECKey(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	domain := d.Get("domain").(string)
+	log.Printf("[DEBUG] Reading DNSSEC key %v of domain: %v", d.Id(), domain)
+
+	keys, err := listDNSSECKeys(meta, domain)
+	if isNotFound(err) {
+		log.Printf("[DEBUG] Domain %v of DNSSEC key %v not found. Cleaning local state reference", domain, d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	for _, key := range keys {
+		id, _ := key["id"].(int64)
+		if strconv.FormatInt(id, 10) != d.Id() {
+			continue
+		}
+
+		algorithm, _ := key["algorithm"].(int64)
+		flags, _ := key["flags"].(int64)
+		publicKey, _ := key["public_key"].(string)
+
+		keytag, digest, err := ComputeDS(domain, int(flags), int(algorithm), publicKey)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		d.Set("algorithm", int(algorithm))
+		d.Set("flags", int(flags))
+		d.Set("public_key", normalizePublicKey(publicKey))
+		d.Set("keytag", keytag)
+		d.Set("digest", digest)
+		d.Set("digest_type", dsDigestTypeSHA256)
+		return nil
+	}
+
+	log.Printf("[DEBUG] DNSSEC key %v not found. Cleaning local state reference", d.Id())
+	d.SetId("")
+	return nil
+}
+
+// DeleteDNSSECKey withdraws the key from the registry
+func DeleteDNSSECKey(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	id, err := strconv.ParseInt(d.Id(), 10, 64)
+	if err != nil {
+		return diag.Errorf("Invalid DNSSEC key ID: %s", d.Id())
+	}
+
+	log.Printf("[DEBUG] Deleting DNSSEC key: %v", d.Id())
+	op, err := callOperation(meta, "domain.dnssec.delete", id)
+	if err != nil {
+		return diag.FromErr(wrapAPIError(err, "Cannot delete DNSSEC key %s", d.Id()))
+	}
+	if err := WaitForOperation(ctx, d, meta, op); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// testDNSKEY is the example key of RFC 4509, section 2.3
+const testDNSKEY = `AQOeiiR0GOMYkDshWoSKz9Xz
+	fwJr1AYtsmx3TGkJaNXVbfi/
+	2pHm822aJ5iI9BMzNXxeYCmZ
+	DRD99WYwYqUSdjMmmAphXdvx
+	egXd/M5+X7OrzKBaMbCVdFLU
+	Uh6DhweJBjEVv5f2wwjM9Xzc
+	nOf+EPbtG9DMBmADjFDc2w/r
+	ljwvFw==`
+
+func TestComputeDS(t *testing.T) {
+	keytag, digest, err := ComputeDS("dskey.example.com.", 256, 5, testDNSKEY)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if keytag != 60485 {
+		t.Fatalf("expected key tag 60485, got %d", keytag)
+	}
+	expected := "D4B7D520E7BB5F0F67674A0CCEB1E3E0614B93C4F9E99B8383F6A1E4469DA50A"
+	if digest != expected {
+		t.Fatalf("expected digest %s, got %s", expected, digest)
+	}
+
+	if _, _, err := ComputeDS("example.com", 257, 8, "not base64!"); err == nil {
+		t.Fatalf("expected an invalid public key to fail")
+	}
+}
+
+func TestAccGandiDNSSECKey(t *testing.T) {
+	domainName := os.Getenv("GANDI_DOMAIN")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckDomain(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckGandiDNSSECKeyDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: fmt.Sprintf(testGandiDNSSECKeyConfig, domainName, normalizePublicKey(testDNSKEY)),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"gandi_dnssec_key.test", "domain", domainName),
+					resource.TestCheckResourceAttr(
+						"gandi_dnssec_key.test", "flags", "257"),
+					resource.TestCheckResourceAttr(
+						"gandi_dnssec_key.test", "digest_type", "2"),
+				),
+			},
+			resource.TestStep{
+				ResourceName:      "gandi_dnssec_key.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					rs := s.RootModule().Resources["gandi_dnssec_key.test"]
+					return rs.Primary.Attributes["domain"] + "/" + rs.Primary.ID, nil
+				},
+				ImportStateVerifyIgnore: []string{"operation_id"},
+			},
+		},
+	})
+}
+
+func TestImportDNSSECKey(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceDNSSECKey().Schema, map[string]interface{}{})
+	d.SetId("example.com/7")
+
+	states, err := importDNSSECKey(context.Background(), d, nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(states) != 1 || states[0].Id() != "7" || states[0].Get("domain").(string) != "example.com" {
+		t.Fatalf("expected key 7 of example.com, got: %s %v", states[0].Id(), states[0].Get("domain"))
+	}
+
+	for _, id := range []string{"7", "example.com", "/7", "example.com/", "example.com/key", "example.com/7/1"} {
+		d.SetId(id)
+		if _, err := importDNSSECKey(context.Background(), d, nil); err == nil {
+			t.Fatalf("expected import ID %q to be refused", id)
+		}
+	}
+}
+
+func testAccCheckGandiDNSSECKeyDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "gandi_dnssec_key" {
+			continue
+		}
+
+		keys, err := listDNSSECKeys(testAccProvider.Meta(), rs.Primary.Attributes["domain"])
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			if id, _ := key["id"].(int64); fmt.Sprint(id) == rs.Primary.ID {
+				return fmt.Errorf("DNSSEC key still exists")
+			}
+		}
+	}
+
+	return nil
+}
+
+const testGandiDNSSECKeyConfig = `
+resource "gandi_dnssec_key" "test" {
+  domain = "%s"
+  algorithm = 5
+  flags = 257
+  public_key = "%s"
+}`
@@ -0,0 +1,290 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// domainContactFields maps the contact attributes of gandi_domain to the
+// contact types of the Gandi API
+var domainContactFields = map[string]string{
+	"owner":   "owner",
+	"admin":   "admin",
+	"tech":    "tech",
+	"billing": "bill",
+}
+
+func resourceDomain() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateDomain,
+		UpdateContext: UpdateDomain,
+		ReadContext:   ReadRegisteredDomain,
+		DeleteContext: DeleteDomain,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		CustomizeDiff: CustomizeDomainDiff,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"duration": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1,
+				Description:  "Years the domain is registered for, raising it renews the domain for the added years. It cannot be lowered. An imported domain adopts the configured duration without renewing.",
+				ValidateFunc: validateRenewDuration,
+			},
+			"owner": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Contact handle of the owner, it cannot be changed without a change of ownership.",
+			},
+			"admin": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Contact handle, defaults to the owner.",
+			},
+			"tech": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Contact handle, defaults to the owner.",
+			},
+			"billing": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Contact handle, defaults to the owner.",
+			},
+			"nameservers": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"zone_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"expires": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"operation_id": operationIDSchema(),
+		},
+	}
+}
+
+// getDomainContacts returns the configured contacts, by Gandi contact type,
+// defaulting to the owner
+func getDomainContacts(d *schema.ResourceData) map[string]interface{} {
+	owner := d.Get("owner").(string)
+
+	contacts := make(map[string]interface{})
+	for attr, field := range domainContactFields {
+		contacts[field] = owner
+		if v, ok := d.GetOk(attr); ok {
+			contacts[field] = v.(string)
+		}
+	}
+	return contacts
+}
+
+// setDomainZone makes the domain use a zone of the account
+func setDomainZone(meta interface{}, name string, zoneID string) error {
+	c := meta.(*Meta).Client
+
+	id, err := strconv.ParseInt(zoneID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("Invalid zone ID: %s", zoneID)
+	}
+
+	log.Printf("[DEBUG] Setting zone of %s: %v", name, id)
+	var res map[string]interface{}
+	if err := c.Call("domain.zone.set", []interface{}{c.Key, name, id}, &res); err != nil {
+		return wrapAPIError(err, "Cannot set zone of %s", name)
+	}
+	return nil
+}
+
+// CreateDomain registers the domain and waits for the registration
+func CreateDomain(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	name := d.Get("name").(string)
+
+	params := getDomainContacts(d)
+	params["duration"] = d.Get("duration").(int)
+	if v, ok := d.GetOk("nameservers"); ok {
+		params["nameservers"] = toStringList(v)
+	}
+
+	log.Printf("[DEBUG] Registering domain %s for %d years", name, params["duration"])
+	op, err := callOperation(meta, "domain.create", name, params)
+	if err != nil {
+		return diag.FromErr(wrapAPIError(err, "Cannot register domain %s", name))
+	}
+
+	d.SetId(name)
+	if err := WaitForOperation(ctx, d, meta, op); err != nil {
+		return diag.FromErr(err)
+	}
+	log.Printf("[INFO] Registered domain: %v", d.Id())
+
+	if v, ok := d.GetOk("zone_id"); ok {
+		if err := setDomainZone(meta, name, v.(string)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return ReadRegisteredDomain(ctx, d, meta)
+}
+
+// ReadRegisteredDomain fetches the contacts, delegation and expiry of the
+// domain
+func ReadRegisteredDomain(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*Meta).Client
+	log.Printf("[DEBUG] Reading domain: %v", d.Id())
+
+	var res map[string]interface{}
+	if err := c.Call("domain.info", []interface{}{c.Key, d.Id()}, &res); err != nil {
+		if isNotFound(err) {
+			// expired or transferred away
+			log.Printf("[DEBUG] Domain %v not found. Cleaning local state reference", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(wrapAPIError(err, "Cannot read domain %s", d.Id()))
+	}
+
+	contacts, _ := res["contacts"].(map[string]interface{})
+	for attr, field := range domainContactFields {
+		contact, _ := contacts[field].(map[string]interface{})
+		handle, _ := contact["handle"].(string)
+		d.Set(attr, handle)
+	}
+
+	d.Set("name", d.Id())
+	d.Set("nameservers", toStringList(res["nameservers"]))
+	if zoneID, ok := res["zone_id"].(int64); ok && zoneID != 0 {
+		d.Set("zone_id", strconv.FormatInt(zoneID, 10))
+	} else {
+		d.Set("zone_id", "")
+	}
+	if expires, ok := res["date_registry_end"].(time.Time); ok {
+		d.Set("expires", expires.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// CustomizeDomainDiff refuses during plan the changes an update cannot
+// make: lowering the duration, since a registration cannot be shortened and
+// every renewal is charged, and changing the owner
+func CustomizeDomainDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+
+	if d.HasChange("owner") {
+		o, n := d.GetChange("owner")
+		return fmt.Errorf("Cannot change owner of %s from %s to %s: a change of ownership must be requested at Gandi",
+			d.Id(), o, n)
+	}
+
+	if o, n := d.GetChange("duration"); n.(int) < o.(int) {
+		return fmt.Errorf("Cannot lower duration of %s from %d to %d years: a registration cannot be shortened",
+			d.Id(), o, n)
+	}
+	return nil
+}
+
+// renewDomain renews the domain for the given years
+func renewDomain(ctx context.Context, d *schema.ResourceData, meta interface{}, years int) error {
+	c := meta.(*Meta).Client
+
+	var res map[string]interface{}
+	if err := c.Call("domain.info", []interface{}{c.Key, d.Id()}, &res); err != nil {
+		return wrapAPIError(err, "Cannot read domain %s", d.Id())
+	}
+	expires, ok := res["date_registry_end"].(time.Time)
+	if !ok {
+		return fmt.Errorf("Cannot renew domain %s: unknown expiration date", d.Id())
+	}
+
+	params := map[string]interface{}{
+		"duration":     years,
+		"current_year": expires.Year(),
+	}
+
+	// Read never refreshes the duration, so the previous state is kept until
+	// Gandi accepted the renewal: a failed request is retried by the next
+	// apply, an accepted one is charged and must not be
+	d.Partial(true)
+	log.Printf("[DEBUG] Renewing domain %s for %d years", d.Id(), params["duration"])
+	op, err := callOperation(meta, "domain.renew", d.Id(), params)
+	if err != nil {
+		return wrapAPIError(err, "Cannot renew domain %s", d.Id())
+	}
+	d.Partial(false)
+
+	return WaitForOperation(ctx, d, meta, op)
+}
+
+// UpdateDomain renews the domain and changes its contacts, nameservers
+// and zone, CustomizeDomainDiff refuses owner changes
+func UpdateDomain(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	// only a longer duration renews, CustomizeDomainDiff refuses shorter ones.
+	// An imported domain has no duration yet, the configured one is recorded.
+	if o, n := d.GetChange("duration"); o.(int) > 0 && n.(int) > o.(int) {
+		if err := renewDomain(ctx, d, meta, n.(int)-o.(int)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChange("admin") || d.HasChange("tech") || d.HasChange("billing") {
+		contacts := getDomainContacts(d)
+		delete(contacts, "owner")
+
+		log.Printf("[DEBUG] Setting contacts of %s: %v", d.Id(), contacts)
+		op, err := callOperation(meta, "domain.contacts.set", d.Id(), contacts)
+		if err != nil {
+			return diag.FromErr(wrapAPIError(err, "Cannot set contacts of %s", d.Id()))
+		}
+		if err := WaitForOperation(ctx, d, meta, op); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChange("nameservers") {
+		if err := setDomainNameservers(ctx, d, meta, d.Id()); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if d.HasChange("zone_id") && d.Get("zone_id").(string) != "" {
+		if err := setDomainZone(meta, d.Id(), d.Get("zone_id").(string)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return ReadRegisteredDomain(ctx, d, meta)
+}
+
+// DeleteDomain stops managing the domain, a registration is never deleted
+// and lasts until it expires
+func DeleteDomain(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] Leaving registration of domain %v in place", d.Id())
+	d.SetId("")
+	return nil
+}
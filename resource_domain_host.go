@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceDomainHost() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateDomainHost,
+		UpdateContext: UpdateDomainHost,
+		ReadContext:   ReadDomainHost,
+		DeleteContext: DeleteDomainHost,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"hostname": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Fully qualified name of the host, under a domain of the account.",
+			},
+			"ips": &schema.Schema{
+				Type:        schema.TypeSet,
+				Required:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "Addresses of the host, in no particular order since Gandi does not keep it.",
+			},
+			"operation_id": operationIDSchema(),
+		},
+	}
+}
+
+// getHostIPs returns the configured addresses, IPv4 or IPv6
+func getHostIPs(d *schema.ResourceData) ([]string, error) {
+	var ips []string
+	for _, v := range d.Get("ips").(*schema.Set).List() {
+		ip := v.(string)
+		if net.ParseIP(ip) == nil {
+			return nil, fmt.Errorf("Invalid IP address for host %s: %s", d.Get("hostname"), ip)
+		}
+		ips = append(ips, ip)
+	}
+	return ips, nil
+}
+
+// CreateDomainHost registers a glue record at the registry
+func CreateDomainHost(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	hostname := d.Get("hostname").(string)
+	ips, err := getHostIPs(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[DEBUG] Creating host %s with IPs: %v", hostname, ips)
+	op, err := callOperation(meta, "domain.host.create", hostname, ips)
+	if err != nil {
+		return diag.FromErr(wrapAPIError(err, "Cannot create host %s", hostname))
+	}
+
+	d.SetId(hostname)
+	if err := WaitForOperation(ctx, d, meta, op); err != nil {
+		return diag.FromErr(err)
+	}
+	log.Printf("[INFO] Created host: %v", d.Id())
+
+	return ReadDomainHost(ctx, d, meta)
+}
+
+// ReadDomainHost fetches the addresses registered for the host
+func ReadDomainHost(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*Meta).Client
+	log.Printf("[DEBUG] Reading host: %v", d.Id())
+
+	var res map[string]interface{}
+	if err := c.Call("domain.host.info", []interface{}{c.Key, d.Id()}, &res); err != nil {
+		if isNotFound(err) {
+			log.Printf("[DEBUG] Host %v not found. Cleaning local state reference", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(wrapAPIError(err, "Cannot read host %s", d.Id()))
+	}
+
+	// Keep the configured spelling of addresses Gandi normalized,
+	// e.g. IPv6 in upper case or with leading zeros
+	configured, _ := getHostIPs(d)
+	var ips []string
+	for _, v := range toStringList(res["ips"]) {
+		ip := v
+		for _, configuredIP := range configured {
+			if net.ParseIP(configuredIP).Equal(net.ParseIP(v)) {
+				ip = configuredIP
+				break
+			}
+		}
+		ips = append(ips, ip)
+	}
+
+	d.Set("hostname", d.Id())
+	d.Set("ips", ips)
+
+	return nil
+}
+
+// UpdateDomainHost changes the addresses of the host
+func UpdateDomainHost(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if d.HasChange("ips") {
+		ips, err := getHostIPs(d)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		log.Printf("[DEBUG] Updating host %s with IPs: %v", d.Id(), ips)
+		op, err := callOperation(meta, "domain.host.update", d.Id(), ips)
+		if err != nil {
+			return diag.FromErr(wrapAPIError(err, "Cannot update host %s", d.Id()))
+		}
+		if err := WaitForOperation(ctx, d, meta, op); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return ReadDomainHost(ctx, d, meta)
+}
+
+// DeleteDomainHost removes the glue record from the registry
+func DeleteDomainHost(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] Deleting host: %v", d.Id())
+
+	op, err := callOperation(meta, "domain.host.delete", d.Id())
+	if err != nil {
+		return diag.FromErr(wrapAPIError(err, "Cannot delete host %s", d.Id()))
+	}
+	if err := WaitForOperation(ctx, d, meta, op); err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[DEBUG] Deleted host: %v", d.Id())
+	d.SetId("")
+	return nil
+}
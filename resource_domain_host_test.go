@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccGandiDomainHost(t *testing.T) {
+	hostname := "ns1." + os.Getenv("GANDI_DOMAIN")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckDomain(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckGandiDomainHostDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: fmt.Sprintf(testGandiDomainHostConfig, hostname, `"192.0.2.1"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"gandi_domain_host.test", "hostname", hostname),
+					resource.TestCheckResourceAttr(
+						"gandi_domain_host.test", "ips.#", "1"),
+					resource.TestCheckTypeSetElemAttr(
+						"gandi_domain_host.test", "ips.*", "192.0.2.1"),
+				),
+			},
+			resource.TestStep{
+				Config: fmt.Sprintf(testGandiDomainHostConfig, hostname, `"192.0.2.1", "2001:DB8::1"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"gandi_domain_host.test", "ips.#", "2"),
+					resource.TestCheckTypeSetElemAttr(
+						"gandi_domain_host.test", "ips.*", "2001:DB8::1"),
+				),
+			},
+		},
+	})
+}
+
+func TestReadDomainHost(t *testing.T) {
+	// Gandi returns the addresses normalized and in its own order
+	meta := testGandiMeta(t, http.StatusOK, testXMLRPCResponse(`<struct>`+
+		`<member><name>ips</name><value><array><data>`+
+		`<value><string>2001:db8::1</string></value>`+
+		`<value><string>192.0.2.1</string></value>`+
+		`</data></array></value></member></struct>`))
+
+	d := schema.TestResourceDataRaw(t, resourceDomainHost().Schema, map[string]interface{}{
+		"hostname": "ns1.example.com",
+		"ips":      []interface{}{"192.0.2.1", "2001:DB8::1"},
+	})
+	d.SetId("ns1.example.com")
+
+	if diags := ReadDomainHost(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("err: %v", diags)
+	}
+
+	ips := d.Get("ips").(*schema.Set)
+	if ips.Len() != 2 || !ips.Contains("192.0.2.1") || !ips.Contains("2001:DB8::1") {
+		t.Fatalf("expected the configured addresses, got %v", ips.List())
+	}
+}
+
+func testAccCheckGandiDomainHostDestroy(s *terraform.State) error {
+	c := testAccProvider.Meta().(*Meta).Client
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "gandi_domain_host" {
+			continue
+		}
+
+		var res map[string]interface{}
+		if err := c.Call("domain.host.info", []interface{}{c.Key, rs.Primary.ID}, &res); err == nil {
+			return fmt.Errorf("Host still exists")
+		}
+	}
+
+	return nil
+}
+
+const testGandiDomainHostConfig = `
+resource "gandi_domain_host" "test" {
+  hostname = "%s"
+  ips = [%s]
+}`
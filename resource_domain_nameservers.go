@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/bemehow/terraform-provider-gandi/gandi/domain"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceDomainNameservers() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateDomainNameservers,
+		UpdateContext: UpdateDomainNameservers,
+		ReadContext:   ReadDomainNameservers,
+		DeleteContext: DeleteDomainNameservers,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"domain": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"nameservers": &schema.Schema{
+				Type:     schema.TypeList,
+				Required: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"operation_id": operationIDSchema(),
+		},
+	}
+}
+
+// getDomainClient wraps Gandi Client in Domain Resource Methods
+func getDomainClient(meta interface{}) *domain.Domain {
+	return domain.New(meta.(*Meta).Client)
+}
+
+// setDomainNameservers delegates the domain to the configured nameservers
+// and waits for the registry update
+func setDomainNameservers(ctx context.Context, d *schema.ResourceData, meta interface{}, name string) error {
+	var nameservers []string
+	for _, ns := range d.Get("nameservers").([]interface{}) {
+		nameservers = append(nameservers, ns.(string))
+	}
+
+	log.Printf("[DEBUG] Setting nameservers of %s: %v", name, nameservers)
+	op, err := callOperation(meta, "domain.nameservers.set", name, nameservers)
+	if err != nil {
+		return wrapAPIError(err, "Cannot set nameservers of %s", name)
+	}
+
+	return WaitForOperation(ctx, d, meta, op)
+}
+
+// CreateDomainNameservers takes over the delegation of the domain
+func CreateDomainNameservers(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if err := setDomainNameservers(ctx, d, meta, d.Get("domain").(string)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(d.Get("domain").(string))
+	log.Printf("[INFO] Set nameservers of domain: %v", d.Id())
+
+	return ReadDomainNameservers(ctx, d, meta)
+}
+
+// ReadDomainNameservers fetches the delegation registered for the domain
+func ReadDomainNameservers(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] Reading nameservers of domain: %v", d.Id())
+
+	info, err := getDomainClient(meta).Info(d.Id())
+	if isNotFound(err) {
+		log.Printf("[DEBUG] Domain %v not found. Cleaning local state reference", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(wrapAPIError(err, "Cannot read domain %s", d.Id()))
+	}
+
+	d.Set("domain", info.Fqdn)
+	d.Set("nameservers", info.Nameservers)
+
+	return nil
+}
+
+// UpdateDomainNameservers changes the delegation of the domain
+func UpdateDomainNameservers(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if d.HasChange("nameservers") {
+		if err := setDomainNameservers(ctx, d, meta, d.Get("domain").(string)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return ReadDomainNameservers(ctx, d, meta)
+}
+
+// DeleteDomainNameservers stops managing the delegation, a domain cannot be
+// left without nameservers so the current ones stay in place
+func DeleteDomainNameservers(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] Leaving nameservers of domain %v unchanged", d.Id())
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccGandiDomainNameservers(t *testing.T) {
+	domainName := os.Getenv("GANDI_DOMAIN")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckDomain(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: fmt.Sprintf(testGandiDomainNameserversConfig, domainName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGandiDomainNameservers("gandi_domain_nameservers.test",
+						[]string{"a.dns.gandi.net", "b.dns.gandi.net", "c.dns.gandi.net"}),
+					resource.TestCheckResourceAttr(
+						"gandi_domain_nameservers.test", "domain", domainName),
+					resource.TestCheckResourceAttr(
+						"gandi_domain_nameservers.test", "nameservers.#", "3"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckGandiDomainNameservers(n string, expected []string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No Domain is set")
+		}
+
+		info, err := getDomainClient(testAccProvider.Meta()).Info(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		if fmt.Sprint(info.Nameservers) != fmt.Sprint(expected) {
+			return fmt.Errorf("Expected nameservers %v, got %v", expected, info.Nameservers)
+		}
+
+		return nil
+	}
+}
+
+const testGandiDomainNameserversConfig = `
+resource "gandi_domain_nameservers" "test" {
+  domain = "%s"
+  nameservers = ["a.dns.gandi.net", "b.dns.gandi.net", "c.dns.gandi.net"]
+}`
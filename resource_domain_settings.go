@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// statusTransferLocked is the registry status of a domain locked against
+// transfers
+const statusTransferLocked = "clientTransferProhibited"
+
+func resourceDomainSettings() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateDomainSettings,
+		UpdateContext: UpdateDomainSettings,
+		ReadContext:   ReadDomainSettings,
+		DeleteContext: DeleteDomainSettings,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"domain": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"autorenew": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Renew the domain automatically before it expires.",
+			},
+			"transfer_lock": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"operation_id": operationIDSchema(),
+		},
+	}
+}
+
+func validateRenewDuration(v interface{}, k string) (ws []string, es []error) {
+	if years := v.(int); years < 1 || years > 10 {
+		es = append(es, fmt.Errorf("%q must be between 1 and 10 years, got: %d", k, years))
+	}
+	return
+}
+
+// DomainSettings are the renewal and lock settings of a domain
+type DomainSettings struct {
+	Autorenew    bool
+	TransferLock bool
+}
+
+// getDomainSettings reads the settings from domain.info, autorenew is absent
+// for domains it was never enabled for
+func getDomainSettings(meta interface{}, name string) (*DomainSettings, error) {
+	c := meta.(*Meta).Client
+
+	var res map[string]interface{}
+	if err := c.Call("domain.info", []interface{}{c.Key, name}, &res); err != nil {
+		return nil, wrapAPIError(err, "Cannot read domain %s", name)
+	}
+
+	s := &DomainSettings{}
+	if autorenew, ok := res["autorenew"].(map[string]interface{}); ok {
+		s.Autorenew, _ = autorenew["active"].(bool)
+	}
+	for _, status := range toStringList(res["status"]) {
+		if status == statusTransferLocked {
+			s.TransferLock = true
+		}
+	}
+
+	return s, nil
+}
+
+// setDomainAutorenew enables or disables the renewal of the domain
+func setDomainAutorenew(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*Meta).Client
+	name := d.Get("domain").(string)
+
+	var res map[string]interface{}
+	if !d.Get("autorenew").(bool) {
+		log.Printf("[DEBUG] Disabling autorenew of domain: %v", name)
+		if err := c.Call("domain.autorenew.deactivate", []interface{}{c.Key, name}, &res); err != nil {
+			return wrapAPIError(err, "Cannot disable autorenew of %s", name)
+		}
+		return nil
+	}
+
+	log.Printf("[DEBUG] Enabling autorenew of domain: %v", name)
+	if err := c.Call("domain.autorenew.activate", []interface{}{c.Key, name}, &res); err != nil {
+		return wrapAPIError(err, "Cannot enable autorenew of %s", name)
+	}
+
+	return nil
+}
+
+// setDomainTransferLock locks or unlocks the domain and waits for the registry
+func setDomainTransferLock(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	name := d.Get("domain").(string)
+
+	method := "domain.status.unlock"
+	if d.Get("transfer_lock").(bool) {
+		method = "domain.status.lock"
+	}
+
+	log.Printf("[DEBUG] Calling %s for domain: %v", method, name)
+	op, err := callOperation(meta, method, name)
+	if err != nil {
+		return wrapAPIError(err, "Cannot change transfer lock of %s", name)
+	}
+
+	return WaitForOperation(ctx, d, meta, op)
+}
+
+// CreateDomainSettings applies the settings the domain does not have yet
+func CreateDomainSettings(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	name := d.Get("domain").(string)
+
+	current, err := getDomainSettings(meta, name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if current.Autorenew != d.Get("autorenew").(bool) {
+		if err := setDomainAutorenew(d, meta); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if current.TransferLock != d.Get("transfer_lock").(bool) {
+		if err := setDomainTransferLock(ctx, d, meta); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	d.SetId(name)
+	log.Printf("[INFO] Managing settings of domain: %v", d.Id())
+
+	return ReadDomainSettings(ctx, d, meta)
+}
+
+// ReadDomainSettings fetches the renewal and lock settings of the domain
+func ReadDomainSettings(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] Reading settings of domain: %v", d.Id())
+
+	s, err := getDomainSettings(meta, d.Id())
+	if isNotFound(err) {
+		log.Printf("[DEBUG] Domain %v not found. Cleaning local state reference", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.Set("domain", d.Id())
+	d.Set("autorenew", s.Autorenew)
+	d.Set("transfer_lock", s.TransferLock)
+
+	return nil
+}
+
+// UpdateDomainSettings changes the renewal and lock settings of the domain
+func UpdateDomainSettings(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if d.HasChange("autorenew") {
+		if err := setDomainAutorenew(d, meta); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if d.HasChange("transfer_lock") {
+		if err := setDomainTransferLock(ctx, d, meta); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return ReadDomainSettings(ctx, d, meta)
+}
+
+// DeleteDomainSettings stops managing the settings, they are left as they
+// are rather than risk the domain expiring or being transferred away
+func DeleteDomainSettings(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] Leaving settings of domain %v unchanged", d.Id())
+	d.SetId("")
+	return nil
+}
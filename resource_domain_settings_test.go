@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccGandiDomainSettings(t *testing.T) {
+	domainName := os.Getenv("GANDI_DOMAIN")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckDomain(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: fmt.Sprintf(testGandiDomainSettingsConfig, domainName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckGandiDomainSettings("gandi_domain_settings.test", true, true),
+					resource.TestCheckResourceAttr(
+						"gandi_domain_settings.test", "autorenew", "true"),
+					resource.TestCheckResourceAttr(
+						"gandi_domain_settings.test", "transfer_lock", "true"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckGandiDomainSettings(n string, autorenew bool, transferLock bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No Domain is set")
+		}
+
+		settings, err := getDomainSettings(testAccProvider.Meta(), rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		if settings.Autorenew != autorenew {
+			return fmt.Errorf("Expected autorenew %v, got %v", autorenew, settings.Autorenew)
+		}
+		if settings.TransferLock != transferLock {
+			return fmt.Errorf("Expected transfer lock %v, got %v", transferLock, settings.TransferLock)
+		}
+
+		return nil
+	}
+}
+
+const testGandiDomainSettingsConfig = `
+resource "gandi_domain_settings" "test" {
+  domain = "%s"
+  autorenew = true
+  transfer_lock = true
+}`
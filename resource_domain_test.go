@@ -0,0 +1,215 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccGandiDomain(t *testing.T) {
+	domainName := fmt.Sprintf("tf-test-%d.com", time.Now().Unix())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			// registrations are only free in the test environment
+			if testingEnv, _ := strconv.ParseBool(os.Getenv("GANDI_TESTING")); !testingEnv {
+				t.Skip("GANDI_TESTING must be true to register domains")
+			}
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckGandiDomainKept,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: fmt.Sprintf(testGandiDomainConfig, domainName, domainName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"data.gandi_domain_availability.test", "available", "true"),
+					resource.TestCheckResourceAttr(
+						"gandi_domain.test", "name", domainName),
+					resource.TestCheckResourceAttr(
+						"gandi_domain.test", "nameservers.#", "3"),
+				),
+			},
+		},
+	})
+}
+
+// testAccCheckGandiDomainKept makes sure destroying a domain left the
+// registration in place
+func testAccCheckGandiDomainKept(s *terraform.State) error {
+	c := testAccProvider.Meta().(*Meta).Client
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "gandi_domain" {
+			continue
+		}
+
+		var res map[string]interface{}
+		if err := c.Call("domain.info", []interface{}{c.Key, rs.Primary.ID}, &res); err != nil {
+			return fmt.Errorf("Domain %s not kept after destroy: %v", rs.Primary.ID, err)
+		}
+	}
+
+	return nil
+}
+
+const testGandiDomainConfig = `
+data "gandi_domain_availability" "test" {
+  name = "%s"
+}
+
+resource "gandi_contact" "test" {
+  given_name = "Terraform"
+  family_name = "Test"
+  street_address = "63-65 boulevard Massena"
+  zip = "75013"
+  city = "Paris"
+  country = "FR"
+  email = "terraform@example.com"
+  phone = "+33.170377661"
+  password = "Terraform-Test-42"
+}
+
+resource "gandi_domain" "test" {
+  name = "%s"
+  owner = "${gandi_contact.test.id}"
+  nameservers = ["a.dns.gandi.net", "b.dns.gandi.net", "c.dns.gandi.net"]
+}`
+
+func TestCustomizeDomainDiff(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "example.com",
+		Attributes: map[string]string{
+			"name":     "example.com",
+			"duration": "3",
+			"owner":    "OWN1-GANDI",
+		},
+	}
+
+	for duration, refused := range map[int]bool{1: true, 3: false, 5: false} {
+		config := terraform.NewResourceConfigRaw(map[string]interface{}{
+			"name":     "example.com",
+			"duration": duration,
+			"owner":    "OWN1-GANDI",
+		})
+		_, err := resourceDomain().Diff(context.Background(), state, config, nil)
+		if refused && (err == nil || !strings.Contains(err.Error(), "Cannot lower duration of example.com from 3 to 1 years")) {
+			t.Fatalf("expected lowering the duration to %d to be refused, got: %v", duration, err)
+		}
+		if !refused && err != nil {
+			t.Fatalf("expected a duration of %d to be accepted, got: %s", duration, err)
+		}
+	}
+
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":     "example.com",
+		"duration": 3,
+		"owner":    "NEW1-GANDI",
+	})
+	_, err := resourceDomain().Diff(context.Background(), state, config, nil)
+	if err == nil || !strings.Contains(err.Error(), "Cannot change owner of example.com from OWN1-GANDI to NEW1-GANDI") {
+		t.Fatalf("expected the owner change to be refused during plan, got: %v", err)
+	}
+}
+
+// testDomainUpdate returns the data of an update of gandi_domain from a
+// state with the given duration to the configured one
+func testDomainUpdate(t *testing.T, stateDuration string, configDuration int) *schema.ResourceData {
+	r := resourceDomain()
+	state := &terraform.InstanceState{
+		ID: "example.com",
+		Attributes: map[string]string{
+			"name":     "example.com",
+			"duration": stateDuration,
+			"owner":    "OWN1-GANDI",
+		},
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":     "example.com",
+		"duration": configDuration,
+		"owner":    "OWN1-GANDI",
+	})
+
+	diff, err := r.Diff(context.Background(), state, config, nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	d, err := schema.InternalMap(r.Schema).Data(state, diff)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	return d
+}
+
+const testDomainInfo = `<struct>` +
+	`<member><name>fqdn</name><value><string>example.com</string></value></member>` +
+	`<member><name>date_registry_end</name><value><dateTime.iso8601>20300102T03:04:05</dateTime.iso8601></value></member>` +
+	`</struct>`
+
+func TestUpdateDomainImported(t *testing.T) {
+	var calls []string
+	meta := testGandiMethods(t, map[string]string{
+		"domain.info": testXMLRPCResponse(testDomainInfo),
+	}, &calls)
+
+	// an imported domain has no duration in its state
+	d := testDomainUpdate(t, "0", 1)
+	if diags := UpdateDomain(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("err: %v", diags)
+	}
+
+	for _, call := range calls {
+		if call == "domain.renew" {
+			t.Fatalf("expected no renewal of an imported domain, got calls: %v", calls)
+		}
+	}
+	if d.Get("duration").(int) != 1 {
+		t.Fatalf("expected the configured duration to be recorded, got %v", d.Get("duration"))
+	}
+}
+
+func TestUpdateDomainRenewalFails(t *testing.T) {
+	var calls []string
+	meta := testGandiMethods(t, map[string]string{
+		"domain.info":  testXMLRPCResponse(testDomainInfo),
+		"domain.renew": testXMLRPCFaultOf(510150, "Error on object : OBJECT_ACCOUNT (CAUSE_BADPARAMETER) [not enough credit]"),
+	}, &calls)
+
+	d := testDomainUpdate(t, "1", 3)
+	if diags := UpdateDomain(context.Background(), d, meta); !diags.HasError() {
+		t.Fatalf("expected the renewal to fail, got calls: %v", calls)
+	}
+
+	// the failed renewal must be retried, not recorded as done
+	if duration := d.State().Attributes["duration"]; duration != "1" {
+		t.Fatalf("expected the previous duration to be kept, got %s", duration)
+	}
+}
+
+func TestUpdateDomainRenews(t *testing.T) {
+	var calls []string
+	meta := testGandiMethods(t, map[string]string{
+		"domain.info": testXMLRPCResponse(testDomainInfo),
+		"domain.renew": testXMLRPCResponse(`<struct>` +
+			`<member><name>id</name><value><int>9</int></value></member>` +
+			`<member><name>step</name><value><string>DONE</string></value></member>` +
+			`</struct>`),
+	}, &calls)
+
+	d := testDomainUpdate(t, "1", 3)
+	if diags := UpdateDomain(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("err: %v", diags)
+	}
+	if duration := d.State().Attributes["duration"]; duration != "3" {
+		t.Fatalf("expected the renewed duration, got %s", duration)
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceMailForward() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateMailForward,
+		UpdateContext: UpdateMailForward,
+		ReadContext:   ReadMailForward,
+		DeleteContext: DeleteMailForward,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"domain": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"source": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Local part of the forwarded address, e.g. contact for contact@domain.",
+			},
+			"destinations": &schema.Schema{
+				Type:     schema.TypeList,
+				Required: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+// mailAddressID identifies a local part of a domain as local@domain
+func mailAddressID(local string, domain string) string {
+	return local + "@" + domain
+}
+
+// parseMailAddressID splits an ID built by mailAddressID
+func parseMailAddressID(id string) (string, string, error) {
+	i := strings.LastIndex(id, "@")
+	if i <= 0 || i == len(id)-1 {
+		return "", "", fmt.Errorf("Invalid mail address ID: %s", id)
+	}
+	return id[:i], id[i+1:], nil
+}
+
+func getForwardParams(d *schema.ResourceData) map[string]interface{} {
+	var destinations []string
+	for _, v := range d.Get("destinations").([]interface{}) {
+		destinations = append(destinations, v.(string))
+	}
+	return map[string]interface{}{"destinations": destinations}
+}
+
+// CreateMailForward forwards mail sent to source to its destinations
+func CreateMailForward(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*Meta).Client
+	domain := d.Get("domain").(string)
+	source := d.Get("source").(string)
+
+	log.Printf("[DEBUG] Creating mail forward %s of domain: %v", source, domain)
+	var res map[string]interface{}
+	if err := c.Call("domain.forward.create", []interface{}{c.Key, domain, source, getForwardParams(d)}, &res); err != nil {
+		return diag.FromErr(wrapAPIError(err, "Cannot create mail forward %s", mailAddressID(source, domain)))
+	}
+
+	d.SetId(mailAddressID(source, domain))
+	log.Printf("[INFO] Created mail forward: %v", d.Id())
+
+	return ReadMailForward(ctx, d, meta)
+}
+
+// ReadMailForward fetches the destinations of the forward
+func ReadMailForward(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*Meta).Client
+	source, domain, err := parseMailAddressID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[DEBUG] Reading mail forward: %v", d.Id())
+	var res []map[string]interface{}
+	if err := c.Call("domain.forward.list", []interface{}{c.Key, domain}, &res); err != nil {
+		if isNotFound(err) {
+			log.Printf("[DEBUG] Domain of mail forward %v not found. Cleaning local state reference", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(wrapAPIError(err, "Cannot list mail forwards of %s", domain))
+	}
+
+	for _, forward := range res {
+		if s, _ := forward["source"].(string); !strings.EqualFold(s, source) {
+			continue
+		}
+
+		d.Set("domain", domain)
+		d.Set("source", source)
+		d.Set("destinations", toStringList(forward["destinations"]))
+		return nil
+	}
+
+	log.Printf("[DEBUG] Mail forward %v not found. Cleaning local state reference", d.Id())
+	d.SetId("")
+	return nil
+}
+
+// UpdateMailForward changes the destinations of the forward
+func UpdateMailForward(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*Meta).Client
+
+	if d.HasChange("destinations") {
+		log.Printf("[DEBUG] Updating mail forward: %v", d.Id())
+		var res map[string]interface{}
+		params := []interface{}{c.Key, d.Get("domain").(string), d.Get("source").(string), getForwardParams(d)}
+		if err := c.Call("domain.forward.update", params, &res); err != nil {
+			return diag.FromErr(wrapAPIError(err, "Cannot update mail forward %s", d.Id()))
+		}
+	}
+
+	return ReadMailForward(ctx, d, meta)
+}
+
+// DeleteMailForward stops forwarding mail sent to source
+func DeleteMailForward(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*Meta).Client
+
+	log.Printf("[DEBUG] Deleting mail forward: %v", d.Id())
+	var res bool
+	if err := c.Call("domain.forward.delete", []interface{}{c.Key, d.Get("domain").(string), d.Get("source").(string)}, &res); err != nil {
+		return diag.FromErr(wrapAPIError(err, "Cannot delete mail forward %s", d.Id()))
+	}
+
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestParseMailAddressID(t *testing.T) {
+	local, domain, err := parseMailAddressID(mailAddressID("contact", "example.com"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if local != "contact" || domain != "example.com" {
+		t.Fatalf("expected contact and example.com, got %s and %s", local, domain)
+	}
+
+	for _, id := range []string{"example.com", "@example.com", "contact@"} {
+		if _, _, err := parseMailAddressID(id); err == nil {
+			t.Fatalf("expected %q to be rejected", id)
+		}
+	}
+}
+
+func TestAccGandiMailForward(t *testing.T) {
+	domainName := os.Getenv("GANDI_DOMAIN")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckDomain(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckGandiMailForwardDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: fmt.Sprintf(testGandiMailForwardConfig, domainName, `"ops@example.com"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"gandi_mail_forward.test", "source", "tftest"),
+					resource.TestCheckResourceAttr(
+						"gandi_mail_forward.test", "destinations.#", "1"),
+				),
+			},
+			resource.TestStep{
+				Config: fmt.Sprintf(testGandiMailForwardConfig, domainName, `"ops@example.com", "dev@example.com"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"gandi_mail_forward.test", "destinations.#", "2"),
+					resource.TestCheckResourceAttr(
+						"gandi_mail_forward.test", "destinations.1", "dev@example.com"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckGandiMailForwardDestroy(s *terraform.State) error {
+	c := testAccProvider.Meta().(*Meta).Client
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "gandi_mail_forward" {
+			continue
+		}
+
+		var res []map[string]interface{}
+		if err := c.Call("domain.forward.list", []interface{}{c.Key, rs.Primary.Attributes["domain"]}, &res); err != nil {
+			return err
+		}
+		for _, forward := range res {
+			if forward["source"] == rs.Primary.Attributes["source"] {
+				return fmt.Errorf("Mail forward still exists")
+			}
+		}
+	}
+
+	return nil
+}
+
+const testGandiMailForwardConfig = `
+resource "gandi_mail_forward" "test" {
+  domain = "%s"
+  source = "tftest"
+  destinations = [%s]
+}`
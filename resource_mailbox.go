@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceMailbox() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateMailbox,
+		UpdateContext: UpdateMailbox,
+		ReadContext:   ReadMailbox,
+		DeleteContext: DeleteMailbox,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"domain": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"login": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Local part of the mailbox address.",
+			},
+			"password": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Sensitive:   true,
+				Description: "Cannot be read back, changes made outside Terraform are not detected.",
+			},
+			"quota": &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Computed:    true,
+				Description: "Storage granted to the mailbox in MB.",
+			},
+			"fallback_email": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"aliases": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func getMailboxParams(d *schema.ResourceData) map[string]interface{} {
+	password := d.Get("password").(string)
+	logSecrets.Register(password)
+
+	params := map[string]interface{}{
+		"password":       password,
+		"fallback_email": d.Get("fallback_email").(string),
+	}
+	if quota, ok := d.GetOk("quota"); ok {
+		params["quota"] = quota.(int)
+	}
+	return params
+}
+
+// setMailboxAliases replaces the aliases of the mailbox
+func setMailboxAliases(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*Meta).Client
+
+	aliases := []string{}
+	for _, v := range d.Get("aliases").([]interface{}) {
+		aliases = append(aliases, v.(string))
+	}
+
+	log.Printf("[DEBUG] Setting aliases of mailbox %s: %v", d.Id(), aliases)
+	var res map[string]interface{}
+	params := []interface{}{c.Key, d.Get("domain").(string), d.Get("login").(string), aliases}
+	if err := c.Call("domain.mailbox.alias.set", params, &res); err != nil {
+		return wrapAPIError(err, "Cannot set aliases of mailbox %s", d.Id())
+	}
+	return nil
+}
+
+// CreateMailbox creates the mailbox and its aliases
+func CreateMailbox(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*Meta).Client
+	domain := d.Get("domain").(string)
+	login := d.Get("login").(string)
+
+	log.Printf("[DEBUG] Creating mailbox %s of domain: %v", login, domain)
+	var res map[string]interface{}
+	if err := c.Call("domain.mailbox.create", []interface{}{c.Key, domain, login, getMailboxParams(d)}, &res); err != nil {
+		return diag.FromErr(wrapAPIError(err, "Cannot create mailbox %s", mailAddressID(login, domain)))
+	}
+
+	d.SetId(mailAddressID(login, domain))
+	log.Printf("[INFO] Created mailbox: %v", d.Id())
+
+	if len(d.Get("aliases").([]interface{})) > 0 {
+		if err := setMailboxAliases(d, meta); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return ReadMailbox(ctx, d, meta)
+}
+
+// ReadMailbox fetches the settings of the mailbox, the password stays as
+// configured since Gandi never returns it
+func ReadMailbox(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*Meta).Client
+	login, domain, err := parseMailAddressID(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[DEBUG] Reading mailbox: %v", d.Id())
+	var mailboxes []map[string]interface{}
+	if err := c.Call("domain.mailbox.list", []interface{}{c.Key, domain}, &mailboxes); err != nil {
+		if isNotFound(err) {
+			log.Printf("[DEBUG] Domain of mailbox %v not found. Cleaning local state reference", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(wrapAPIError(err, "Cannot list mailboxes of %s", domain))
+	}
+
+	found := false
+	for _, mailbox := range mailboxes {
+		if l, _ := mailbox["login"].(string); strings.EqualFold(l, login) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		log.Printf("[DEBUG] Mailbox %v not found. Cleaning local state reference", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	var res map[string]interface{}
+	if err := c.Call("domain.mailbox.info", []interface{}{c.Key, domain, login}, &res); err != nil {
+		if isNotFound(err) {
+			log.Printf("[DEBUG] Mailbox %v not found. Cleaning local state reference", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(wrapAPIError(err, "Cannot read mailbox %s", d.Id()))
+	}
+
+	d.Set("domain", domain)
+	d.Set("login", login)
+	if quota, ok := res["quota"].(map[string]interface{}); ok {
+		granted, _ := quota["granted"].(int64)
+		d.Set("quota", int(granted))
+	}
+	fallback, _ := res["fallback_email"].(string)
+	d.Set("fallback_email", fallback)
+	d.Set("aliases", toStringList(res["aliases"]))
+
+	return nil
+}
+
+// UpdateMailbox changes the password, quota, fallback and aliases
+func UpdateMailbox(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*Meta).Client
+
+	if d.HasChange("password") || d.HasChange("quota") || d.HasChange("fallback_email") {
+		// The password is never read back, keep the previous state when the
+		// update fails so that the next apply retries it
+		d.Partial(true)
+		log.Printf("[DEBUG] Updating mailbox: %v", d.Id())
+		var res map[string]interface{}
+		params := []interface{}{c.Key, d.Get("domain").(string), d.Get("login").(string), getMailboxParams(d)}
+		if err := c.Call("domain.mailbox.update", params, &res); err != nil {
+			return diag.FromErr(wrapAPIError(err, "Cannot update mailbox %s", d.Id()))
+		}
+		d.Partial(false)
+	}
+
+	if d.HasChange("aliases") {
+		if err := setMailboxAliases(d, meta); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return ReadMailbox(ctx, d, meta)
+}
+
+// DeleteMailbox deletes the mailbox and the mail it holds
+func DeleteMailbox(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*Meta).Client
+
+	log.Printf("[DEBUG] Deleting mailbox: %v", d.Id())
+	var res bool
+	if err := c.Call("domain.mailbox.delete", []interface{}{c.Key, d.Get("domain").(string), d.Get("login").(string)}, &res); err != nil {
+		return diag.FromErr(wrapAPIError(err, "Cannot delete mailbox %s", d.Id()))
+	}
+
+	d.SetId("")
+	return nil
+}
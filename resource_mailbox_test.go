@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccGandiMailbox(t *testing.T) {
+	domainName := os.Getenv("GANDI_DOMAIN")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckDomain(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckGandiMailboxDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: fmt.Sprintf(testGandiMailboxConfig, domainName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"gandi_mailbox.test", "login", "tftest"),
+					resource.TestCheckResourceAttr(
+						"gandi_mailbox.test", "aliases.#", "1"),
+					resource.TestCheckResourceAttr(
+						"gandi_mailbox.test", "aliases.0", "tftest-alias"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckGandiMailboxDestroy(s *terraform.State) error {
+	c := testAccProvider.Meta().(*Meta).Client
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "gandi_mailbox" {
+			continue
+		}
+
+		var res []map[string]interface{}
+		if err := c.Call("domain.mailbox.list", []interface{}{c.Key, rs.Primary.Attributes["domain"]}, &res); err != nil {
+			return err
+		}
+		for _, mailbox := range res {
+			if mailbox["login"] == rs.Primary.Attributes["login"] {
+				return fmt.Errorf("Mailbox still exists")
+			}
+		}
+	}
+
+	return nil
+}
+
+const testGandiMailboxConfig = `
+resource "gandi_mailbox" "test" {
+  domain = "%s"
+  login = "tftest"
+  password = "Terraform-Test-42"
+  aliases = ["tftest-alias"]
+}`
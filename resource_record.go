@@ -1,22 +1,38 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strconv"
 	"strings"
+	"time"
 
-	"github.com/hashicorp/terraform/helper/schema"
-	"github.com/prasmussen/gandi-api/client"
-	"github.com/prasmussen/gandi-api/domain/zone/record"
+	"github.com/bemehow/terraform-provider-gandi/gandi/domain/zone/record"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func resourceRecord() *schema.Resource {
 	return &schema.Resource{
-		Create: CreateRecord,
-		Update: UpdateRecord,
-		Read:   ReadRecord,
-		Delete: DeleteRecord,
+		CreateContext: CreateRecord,
+		UpdateContext: UpdateRecord,
+		ReadContext:   ReadRecord,
+		DeleteContext: DeleteRecord,
+		Importer: &schema.ResourceImporter{
+			StateContext: importRecord,
+		},
+		CustomizeDiff: CustomizeRecordDiff,
+		Description: "Conflicts with records of the zone version that no gandi_record manages are reported during plan. " +
+			"Conflicts with managed records, which the same plan may remove, are only reported when they are applied; " +
+			"a record replacing a conflicting one must be added in a later apply than the removal.",
+		// long enough to copy and activate a zone version, then wait for
+		// propagation with its default timeout
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(15 * time.Minute),
+			Update: schema.DefaultTimeout(15 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
@@ -43,13 +59,59 @@ func resourceRecord() *schema.Resource {
 				Type:     schema.TypeString,
 				Required: true,
 			},
+			"wait_for_propagation": propagationSchema(),
 		},
 	}
 }
 
+// importRecord splits the <zone_id>_<record_id> ID given to terraform
+// import, the record is then read from the active version of the zone
+func importRecord(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.Split(d.Id(), "_")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("Invalid record import ID %q, expected <zone_id>_<record_id>", d.Id())
+	}
+	for _, part := range parts {
+		if _, err := strconv.ParseInt(part, 10, 64); err != nil {
+			return nil, fmt.Errorf("Invalid record import ID %q, expected <zone_id>_<record_id>", d.Id())
+		}
+	}
+
+	d.Set("zone_id", parts[0])
+	d.SetId(parts[1])
+	return []*schema.ResourceData{d}, nil
+}
+
+// CustomizeRecordDiff reports conflicts with the records already in the
+// zone during plan, when the zone, version and value of the record are
+// known. Records of the same plan are not in the zone yet and managed
+// records refreshed by this process may be removed by it, their conflicts
+// are caught at apply, where the check runs again under the zone lock.
+func CustomizeRecordDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	attrs := []string{"zone_id", "version", "name", "type", "value"}
+	for _, attr := range attrs {
+		if !d.NewValueKnown(attr) {
+			return nil
+		}
+	}
+	if d.Id() != "" && !d.HasChanges(attrs...) {
+		return nil
+	}
+
+	zr := ZoneRecord{}
+	zr.Zone, _ = strconv.ParseInt(d.Get("zone_id").(string), 10, 64)
+	zr.Version, _ = strconv.ParseInt(d.Get("version").(string), 10, 64)
+	zr.Id, _ = strconv.ParseInt(d.Id(), 10, 64)
+	zr.Name = d.Get("name").(string)
+	zr.Type = d.Get("type").(string)
+	zr.Value = d.Get("value").(string)
+
+	return checkRecordConflicts(meta.(*Meta).WithContext(ctx), &zr, true)
+}
+
 // getRecordClient wraps Gandi Client in Record Resource Methods
 func getRecordClient(meta interface{}) *record.Record {
-	return record.New(meta.(*client.Client))
+	return record.New(meta.(*Meta).Client)
 }
 
 // ZoneRecord
@@ -98,7 +160,7 @@ func (zr *ZoneRecord) toRecordUpdate() record.RecordUpdate {
 }
 
 // CreateRecord creates new record
-func CreateRecord(d *schema.ResourceData, meta interface{}) error {
+func CreateRecord(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] Entering CreateRecord")
 	var err error
 	var activeVersion int64
@@ -106,13 +168,21 @@ func CreateRecord(d *schema.ResourceData, meta interface{}) error {
 
 	var zr ZoneRecord
 	zr.Parse(d)
+	unlock, err := lockZone(ctx, zr.Zone)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer unlock()
+	if err := checkRecordConflicts(meta, &zr, false); err != nil {
+		return diag.FromErr(err)
+	}
 	if zr.Version == 0 {
 		log.Printf("[DEBUG] Looking for active zone version")
 		version := getZoneVersionClient(meta)
 		_, activeVersion, err = getActiveZoneVersion(meta, zr.Zone)
 		newZoneVersion, err := createZoneVersion(version, zr.Zone, activeVersion, 0)
 		if err != nil {
-			return fmt.Errorf("Could not create new version for record: %v", err)
+			return diag.FromErr(wrapAPIError(err, "Could not create new version for record"))
 		}
 		_, zr.Version = resourceIDSplit(newZoneVersion, "_")
 	}
@@ -120,26 +190,34 @@ func CreateRecord(d *schema.ResourceData, meta interface{}) error {
 	log.Printf("[DEBUG] Creating new record from spec: %+v", zr)
 
 	newRecord, err := client.Add(zr.toRecordAdd())
+	invalidateZone(meta, zr.Zone)
 	if err != nil {
-		return fmt.Errorf("Could not create new record: %v", err)
+		return diag.FromErr(wrapAPIError(err, "Could not create new record"))
 	}
 
 	// Success
 	d.SetId(strconv.FormatInt(newRecord.Id, 10))
+	markManagedRecord(newRecord.Id)
 	log.Printf("[INFO] Successfully created record: %v", d.Id())
 	log.Printf("[INFO] Active zone version: %v New Zone Version: %v", activeVersion, zr.Version)
 	if activeVersion != 0 {
 		setActiveZoneVersion(meta, zr.Zone, zr.Version)
 	}
+	unlock()
+
+	if p := getPropagation(d); p != nil {
+		if err := WaitForPropagation(ctx, &zr, p); err != nil {
+			return diag.FromErr(err)
+		}
+	}
 
-	return ReadRecord(d, meta)
+	return ReadRecord(ctx, d, meta)
 }
 
 func getActiveZoneVersion(meta interface{}, zoneID int64) (string, int64, error) {
-	zone := getZoneClient(meta)
-	zoneInfo, err := zone.Info(zoneID)
+	zoneInfo, err := getZoneInfo(meta, zoneID)
 	if err != nil {
-		return "", 0, fmt.Errorf("Cannot get zone info for zone id: %d, %v", zoneID, err)
+		return "", 0, wrapAPIError(err, "Cannot get zone info for zone id: %d", zoneID)
 	}
 	zoneVersion := strconv.FormatInt(zoneInfo.Version, 10)
 	return zoneVersion, zoneInfo.Version, nil
@@ -148,32 +226,34 @@ func getActiveZoneVersion(meta interface{}, zoneID int64) (string, int64, error)
 func setActiveZoneVersion(meta interface{}, zoneID int64, version int64) error {
 	client := getZoneVersionClient(meta)
 	_, err := client.Set(zoneID, version)
+	invalidateZone(meta, zoneID)
 	return err
 }
 
 // GetRecord returns record if exist in specified zone/version
 func GetRecord(client *record.Record, zoneID interface{}, zoneVersion interface{}, recordID interface{}) (*record.RecordInfo, error) {
+	return getRecord(nil, client, zoneID, zoneVersion, recordID)
+}
+
+// getRecord looks the record up in the records of the zone version, listed
+// once per zone version while cache holds them
+func getRecord(cache *ZoneCache, client *record.Record, zoneID interface{}, zoneVersion interface{}, recordID interface{}) (*record.RecordInfo, error) {
 	var zid, zv, rid int64
 	zid, _ = strconv.ParseInt(zoneID.(string), 10, 64)
 	zv, _ = strconv.ParseInt(zoneVersion.(string), 10, 64)
 	rid, _ = strconv.ParseInt(recordID.(string), 10, 64)
 
-	records, err := client.List(zid, zv)
-
+	r, err := cache.Record(zid, zv, rid, client.List)
 	if err != nil {
-		return nil, fmt.Errorf("Cannot read record: %v", rid)
+		return nil, wrapAPIError(err, "Cannot read record %v from zone: %v version: %v", rid, zid, zv)
 	}
 
-	// TODO: need to implement this to be sorted to improve speed
-	for _, r := range records {
-		if r.Id == rid {
-			log.Printf("[DEBUG] Record found: %v", rid)
-			return r, nil
-		}
+	if r == nil {
+		return nil, newNotFound("Record %v not found in zone: %v version: %v", rid, zid, zv)
 	}
 
-	// not found
-	return nil, fmt.Errorf("Record not found")
+	log.Printf("[DEBUG] Record found: %v", rid)
+	return r, nil
 }
 
 // CheckRecord returns boolean value for record existence
@@ -191,7 +271,7 @@ func CheckRecord(client *record.Record, zoneID interface{}, zoneVersion interfac
 }
 
 // ReadRecord fetches configuration
-func ReadRecord(d *schema.ResourceData, meta interface{}) error {
+func ReadRecord(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] Entering ReadRecord")
 	var err error
 	client := getRecordClient(meta)
@@ -203,33 +283,35 @@ func ReadRecord(d *schema.ResourceData, meta interface{}) error {
 		log.Printf("[DEBUG] Looking for active version of zone %v", zoneID)
 		zid, _ := strconv.ParseInt(zoneID.(string), 10, 64)
 		zoneVersion, _, err = getActiveZoneVersion(meta, zid)
+		if isNotFound(err) {
+			log.Printf("[DEBUG] Zone %v not found. Deleting record from tfstate: %v", zoneID, d.Id())
+			d.SetId("")
+			return nil
+		}
+		if err != nil {
+			return diag.FromErr(err)
+		}
 		log.Printf("[DEBUG] Found active version of zone %v: %#v", zoneID, zoneVersion)
 	}
 	recordID := d.Id()
 
 	log.Printf("[DEBUG] Reading records from zone: %v version: %v", zoneID, zoneVersion)
 
-	record, err := GetRecord(client, zoneID, zoneVersion, recordID)
+	record, err := getRecord(meta.(*Meta).Cache, client, zoneID, zoneVersion, recordID)
 	log.Printf("[DEBUG] %#v", record)
 
 	if err != nil {
-		if strings.Contains(err.Error(), "Record not found") {
-			// not found
+		if isNotFound(err) {
 			log.Printf("[DEBUG] Deleting record from tfstate: %v", recordID)
 			d.SetId("")
 			return nil
 		}
-		return fmt.Errorf("Couldn't find record: %s", err)
+		return diag.FromErr(wrapAPIError(err, "Couldn't find record"))
 	}
 
 	if record != nil {
-		// XXX: Gandi quotes values for SRV and TXT records. They need to be unquoted for comparision
-		value, err := strconv.Unquote(record.Value)
-		// Cannot unquote, no quotes use as is
-		if err != nil {
-			value = record.Value
-		}
-		d.Set("value", value)
+		markManagedRecord(record.Id)
+		d.Set("value", unquoteRecordValue(record.Value))
 		d.Set("name", record.Name)
 		d.Set("ttl", strconv.FormatInt(record.Ttl, 10))
 		d.Set("type", record.Type)
@@ -238,8 +320,19 @@ func ReadRecord(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
+// unquoteRecordValue strips the quotes Gandi adds around SRV and TXT values
+func unquoteRecordValue(value string) string {
+	// XXX: Gandi quotes values for SRV and TXT records. They need to be unquoted for comparision
+	unquoted, err := strconv.Unquote(value)
+	// Cannot unquote, no quotes use as is
+	if err != nil {
+		return value
+	}
+	return unquoted
+}
+
 // UpdateRecord updates record in zone/version according to the new spec
-func UpdateRecord(d *schema.ResourceData, meta interface{}) error {
+func UpdateRecord(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] Entering UpdateRecord")
 	var err error
 	var activeVersion int64
@@ -248,13 +341,21 @@ func UpdateRecord(d *schema.ResourceData, meta interface{}) error {
 	var zr ZoneRecord
 	zr.Parse(d)
 	log.Printf("[DEBUG] FINDME ZoneRecord: %#v", zr)
+	unlock, err := lockZone(ctx, zr.Zone)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer unlock()
+	if err := checkRecordConflicts(meta, &zr, false); err != nil {
+		return diag.FromErr(err)
+	}
 	if zr.Version == 0 {
 		log.Printf("[DEBUG] Looking for active zone version")
 		version := getZoneVersionClient(meta)
 		_, activeVersion, err = getActiveZoneVersion(meta, zr.Zone)
 		newZoneVersion, err := createZoneVersion(version, zr.Zone, activeVersion, 0)
 		if err != nil {
-			return fmt.Errorf("Could not create new version for record: %v", err)
+			return diag.FromErr(wrapAPIError(err, "Could not create new version for record"))
 		}
 		_, zr.Version = resourceIDSplit(newZoneVersion, "_")
 		// Find old record in active version by name, type, value
@@ -281,8 +382,9 @@ func UpdateRecord(d *schema.ResourceData, meta interface{}) error {
 	log.Printf("[DEBUG] Updating record: %v", zr.Id)
 	//TODO: it returns []*record.RecordInfo. Does the driver update more than 1 record at the time?
 	_, err = client.Update(zr.toRecordUpdate())
+	invalidateZone(meta, zr.Zone)
 	if err != nil {
-		return fmt.Errorf("Cannot update record: %v", err)
+		return diag.FromErr(wrapAPIError(err, "Cannot update record"))
 	}
 
 	// Success
@@ -290,11 +392,18 @@ func UpdateRecord(d *schema.ResourceData, meta interface{}) error {
 	if activeVersion != 0 {
 		setActiveZoneVersion(meta, zr.Zone, zr.Version)
 	}
+	unlock()
+
+	if p := getPropagation(d); p != nil {
+		if err := WaitForPropagation(ctx, &zr, p); err != nil {
+			return diag.FromErr(err)
+		}
+	}
 	return nil
 }
 
-//DeleteRecord deletes records from zone version by id
-func DeleteRecord(d *schema.ResourceData, meta interface{}) error {
+// DeleteRecord deletes records from zone version by id
+func DeleteRecord(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] Entering DeleteRecord")
 	var err error
 	var activeVersion int64
@@ -302,13 +411,18 @@ func DeleteRecord(d *schema.ResourceData, meta interface{}) error {
 
 	var zr ZoneRecord
 	zr.Parse(d)
+	unlock, err := lockZone(ctx, zr.Zone)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer unlock()
 	if zr.Version == 0 {
 		log.Printf("[DEBUG] Looking for active zone version")
 		version := getZoneVersionClient(meta)
 		_, activeVersion, err = getActiveZoneVersion(meta, zr.Zone)
 		newZoneVersion, err := createZoneVersion(version, zr.Zone, activeVersion, 0)
 		if err != nil {
-			return fmt.Errorf("Could not create new version for record: %v", err)
+			return diag.FromErr(wrapAPIError(err, "Could not create new version for record"))
 		}
 		_, zr.Version = resourceIDSplit(newZoneVersion, "_")
 		// ID needs to also be updated.
@@ -323,8 +437,14 @@ func DeleteRecord(d *schema.ResourceData, meta interface{}) error {
 
 	log.Printf("[DEBUG] Deleting record: %v", zr.Id)
 	success, err := client.Delete(zr.Zone, zr.Version, zr.Id)
+	invalidateZone(meta, zr.Zone)
+	if isNotFound(err) {
+		log.Printf("[DEBUG] Record %v already deleted", zr.Id)
+		d.SetId("")
+		return nil
+	}
 	if err != nil {
-		return fmt.Errorf("Cannot delete record: %v", err)
+		return diag.FromErr(wrapAPIError(err, "Cannot delete record"))
 	}
 
 	if success {
@@ -338,5 +458,5 @@ func DeleteRecord(d *schema.ResourceData, meta interface{}) error {
 		setActiveZoneVersion(meta, zr.Zone, zr.Version)
 	}
 
-	return err
+	return diag.FromErr(err)
 }
@@ -1,15 +1,17 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
 	"strconv"
 	"testing"
 
-	"github.com/hashicorp/terraform/helper/resource"
-	"github.com/hashicorp/terraform/terraform"
-	"github.com/prasmussen/gandi-api/domain/zone/record"
+	"github.com/bemehow/terraform-provider-gandi/gandi/domain/zone/record"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 func TestAccGandiRecordA(t *testing.T) {
@@ -23,8 +25,8 @@ func TestAccGandiRecordA(t *testing.T) {
 			testAccPreCheck(t)
 			testAccPreCheckRecord(t)
 		},
-		Providers:    testAccProviders,
-		CheckDestroy: testAccCheckGandiRecordDestroy,
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckGandiRecordDestroy,
 		Steps: []resource.TestStep{
 			resource.TestStep{
 				Config: fmt.Sprintf(testGandiRecordConfigA, zoneID, zoneVersion),
@@ -57,8 +59,8 @@ func TestAccGandiRecordCNAME(t *testing.T) {
 			testAccPreCheck(t)
 			testAccPreCheckRecord(t)
 		},
-		Providers:    testAccProviders,
-		CheckDestroy: testAccCheckGandiRecordDestroy,
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckGandiRecordDestroy,
 		Steps: []resource.TestStep{
 			resource.TestStep{
 				Config: fmt.Sprintf(testGandiRecordConfigCNAME, zoneID, zoneVersion),
@@ -91,8 +93,8 @@ func TestAccGandiRecordMX(t *testing.T) {
 			testAccPreCheck(t)
 			testAccPreCheckRecord(t)
 		},
-		Providers:    testAccProviders,
-		CheckDestroy: testAccCheckGandiRecordDestroy,
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckGandiRecordDestroy,
 		Steps: []resource.TestStep{
 			resource.TestStep{
 				Config: fmt.Sprintf(testGandiRecordConfigMX, zoneID, zoneVersion),
@@ -125,8 +127,8 @@ func TestAccGandiRecordTXT(t *testing.T) {
 			testAccPreCheck(t)
 			testAccPreCheckRecord(t)
 		},
-		Providers:    testAccProviders,
-		CheckDestroy: testAccCheckGandiRecordDestroy,
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckGandiRecordDestroy,
 		Steps: []resource.TestStep{
 			resource.TestStep{
 				Config: fmt.Sprintf(testGandiRecordConfigTXT, zoneID, zoneVersion),
@@ -159,8 +161,8 @@ func TestAccGandiRecordSPF(t *testing.T) {
 			testAccPreCheck(t)
 			testAccPreCheckRecord(t)
 		},
-		Providers:    testAccProviders,
-		CheckDestroy: testAccCheckGandiRecordDestroy,
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckGandiRecordDestroy,
 		Steps: []resource.TestStep{
 			resource.TestStep{
 				Config: fmt.Sprintf(testGandiRecordConfigSPF, zoneID, zoneVersion),
@@ -193,8 +195,8 @@ func TestAccGandiRecordNS(t *testing.T) {
 			testAccPreCheck(t)
 			testAccPreCheckRecord(t)
 		},
-		Providers:    testAccProviders,
-		CheckDestroy: testAccCheckGandiRecordDestroy,
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckGandiRecordDestroy,
 		Steps: []resource.TestStep{
 			resource.TestStep{
 				Config: fmt.Sprintf(testGandiRecordConfigNS, zoneID, zoneVersion),
@@ -227,8 +229,8 @@ func TestAccGandiRecordAAAA(t *testing.T) {
 			testAccPreCheck(t)
 			testAccPreCheckRecord(t)
 		},
-		Providers:    testAccProviders,
-		CheckDestroy: testAccCheckGandiRecordDestroy,
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckGandiRecordDestroy,
 		Steps: []resource.TestStep{
 			resource.TestStep{
 				Config: fmt.Sprintf(testGandiRecordConfigAAAA, zoneID, zoneVersion),
@@ -261,8 +263,8 @@ func TestAccGandiRecordSRV(t *testing.T) {
 			testAccPreCheck(t)
 			testAccPreCheckRecord(t)
 		},
-		Providers:    testAccProviders,
-		CheckDestroy: testAccCheckGandiRecordDestroy,
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckGandiRecordDestroy,
 		Steps: []resource.TestStep{
 			resource.TestStep{
 				Config: fmt.Sprintf(testGandiRecordConfigSRV, zoneID, zoneVersion),
@@ -295,8 +297,8 @@ func TestAccGandiRecordModifyAintoCNAME(t *testing.T) {
 			testAccPreCheck(t)
 			testAccPreCheckRecord(t)
 		},
-		Providers:    testAccProviders,
-		CheckDestroy: testAccCheckGandiRecordDestroy,
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckGandiRecordDestroy,
 		Steps: []resource.TestStep{
 			resource.TestStep{
 				Config: fmt.Sprintf(testGandiRecordConfigA, zoneID, zoneVersion),
@@ -474,3 +476,23 @@ func testAccCheckGandiRecordExists(n string, r *record.RecordInfo) resource.Test
 		return nil
 	}
 }
+
+func TestImportRecord(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceRecord().Schema, map[string]interface{}{})
+	d.SetId("42_9")
+
+	states, err := importRecord(context.Background(), d, nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(states) != 1 || states[0].Id() != "9" || states[0].Get("zone_id").(string) != "42" {
+		t.Fatalf("expected record 9 of zone 42, got: %s %v", states[0].Id(), states[0].Get("zone_id"))
+	}
+
+	for _, id := range []string{"9", "42_", "_9", "42_9_1", "zone_9"} {
+		d.SetId(id)
+		if _, err := importRecord(context.Background(), d, nil); err == nil {
+			t.Fatalf("expected import ID %q to be refused", id)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// webRedirectionTypes maps the redirection types to their Gandi names
+var webRedirectionTypes = map[string]string{
+	"permanent": "http301",
+	"temporary": "http302",
+	"cloaked":   "cloak",
+}
+
+func resourceWebRedirection() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateWebRedirection,
+		UpdateContext: UpdateWebRedirection,
+		ReadContext:   ReadWebRedirection,
+		DeleteContext: DeleteWebRedirection,
+		Importer: &schema.ResourceImporter{
+			StateContext: importWebRedirection,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"domain": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"host": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Host redirected, relative to the domain, empty for the domain itself.",
+			},
+			"url": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"type": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "permanent",
+				ValidateFunc: validateWebRedirectionType,
+			},
+		},
+	}
+}
+
+func validateWebRedirectionType(v interface{}, k string) (ws []string, es []error) {
+	if _, ok := webRedirectionTypes[v.(string)]; !ok {
+		es = append(es, fmt.Errorf("%q must be one of permanent, temporary or cloaked, got: %s", k, v))
+	}
+	return
+}
+
+// webRedirectionType returns the redirection type of a Gandi type name
+func webRedirectionType(gandiType string) string {
+	for t, name := range webRedirectionTypes {
+		if strings.EqualFold(name, gandiType) {
+			return t
+		}
+	}
+	return gandiType
+}
+
+func getWebRedirectionParams(d *schema.ResourceData) map[string]interface{} {
+	return map[string]interface{}{
+		"url":  d.Get("url").(string),
+		"type": webRedirectionTypes[d.Get("type").(string)],
+	}
+}
+
+// importWebRedirection splits the FQDN given to terraform import into the
+// host and the domain. The FQDN doesn't tell where the domain starts, so
+// its suffixes are tried from the longest, the first domain listing a
+// redirection of the remaining host is kept.
+func importWebRedirection(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	c := meta.(*Meta).Client
+	fqdn := strings.TrimSuffix(d.Id(), ".")
+	labels := strings.Split(fqdn, ".")
+	if len(labels) < 2 {
+		return nil, fmt.Errorf("Invalid web redirection import ID %q, expected the redirected FQDN", d.Id())
+	}
+
+	for i := 0; i <= len(labels)-2; i++ {
+		host, domain := strings.Join(labels[:i], "."), strings.Join(labels[i:], ".")
+
+		log.Printf("[DEBUG] Looking up web redirection %v in domain: %v", fqdn, domain)
+		var res []map[string]interface{}
+		if err := c.Call("domain.webredir.list", []interface{}{c.Key, domain}, &res); err != nil {
+			if isNotFound(err) {
+				continue
+			}
+			return nil, wrapAPIError(err, "Cannot list web redirections of %s", domain)
+		}
+
+		for _, redirection := range res {
+			if h, _ := redirection["host"].(string); strings.EqualFold(h, host) {
+				d.Set("domain", domain)
+				d.Set("host", h)
+				d.SetId(fqdn)
+				return []*schema.ResourceData{d}, nil
+			}
+		}
+		return nil, newNotFound("Web redirection %s not found in domain %s", fqdn, domain)
+	}
+
+	return nil, newNotFound("No domain of the account holds web redirection %s", fqdn)
+}
+
+// CreateWebRedirection redirects the host to the URL
+func CreateWebRedirection(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*Meta).Client
+	domain := d.Get("domain").(string)
+	host := d.Get("host").(string)
+
+	params := getWebRedirectionParams(d)
+	params["host"] = host
+
+	log.Printf("[DEBUG] Creating web redirection of %s to: %v", recordFQDN(host, domain), params["url"])
+	var res map[string]interface{}
+	if err := c.Call("domain.webredir.create", []interface{}{c.Key, domain, params}, &res); err != nil {
+		return diag.FromErr(wrapAPIError(err, "Cannot create web redirection of %s", recordFQDN(host, domain)))
+	}
+
+	d.SetId(strings.TrimSuffix(recordFQDN(host, domain), "."))
+	log.Printf("[INFO] Created web redirection: %v", d.Id())
+
+	return ReadWebRedirection(ctx, d, meta)
+}
+
+// ReadWebRedirection fetches the target and type of the redirection
+func ReadWebRedirection(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*Meta).Client
+	domain := d.Get("domain").(string)
+	host := d.Get("host").(string)
+
+	log.Printf("[DEBUG] Reading web redirection: %v", d.Id())
+	var res []map[string]interface{}
+	if err := c.Call("domain.webredir.list", []interface{}{c.Key, domain}, &res); err != nil {
+		if isNotFound(err) {
+			log.Printf("[DEBUG] Domain of web redirection %v not found. Cleaning local state reference", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(wrapAPIError(err, "Cannot list web redirections of %s", domain))
+	}
+
+	for _, redirection := range res {
+		if h, _ := redirection["host"].(string); !strings.EqualFold(h, host) {
+			continue
+		}
+
+		url, _ := redirection["url"].(string)
+		gandiType, _ := redirection["type"].(string)
+		d.Set("url", url)
+		d.Set("type", webRedirectionType(gandiType))
+		return nil
+	}
+
+	log.Printf("[DEBUG] Web redirection %v not found. Cleaning local state reference", d.Id())
+	d.SetId("")
+	return nil
+}
+
+// UpdateWebRedirection changes the target or type of the redirection
+func UpdateWebRedirection(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*Meta).Client
+
+	if d.HasChange("url") || d.HasChange("type") {
+		log.Printf("[DEBUG] Updating web redirection: %v", d.Id())
+		var res map[string]interface{}
+		params := []interface{}{c.Key, d.Get("domain").(string), d.Get("host").(string), getWebRedirectionParams(d)}
+		if err := c.Call("domain.webredir.update", params, &res); err != nil {
+			return diag.FromErr(wrapAPIError(err, "Cannot update web redirection %s", d.Id()))
+		}
+	}
+
+	return ReadWebRedirection(ctx, d, meta)
+}
+
+// DeleteWebRedirection removes the redirection
+func DeleteWebRedirection(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*Meta).Client
+
+	log.Printf("[DEBUG] Deleting web redirection: %v", d.Id())
+	var res bool
+	if err := c.Call("domain.webredir.delete", []interface{}{c.Key, d.Get("domain").(string), d.Get("host").(string)}, &res); err != nil {
+		return diag.FromErr(wrapAPIError(err, "Cannot delete web redirection %s", d.Id()))
+	}
+
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/bemehow/terraform-provider-gandi/gandi/client"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestWebRedirectionType(t *testing.T) {
+	for redirectionType, gandiType := range webRedirectionTypes {
+		if got := webRedirectionType(gandiType); got != redirectionType {
+			t.Fatalf("webRedirectionType(%q) = %q, want %q", gandiType, got, redirectionType)
+		}
+	}
+
+	if _, es := validateWebRedirectionType("http301", "type"); len(es) == 0 {
+		t.Fatalf("expected Gandi type names to be rejected")
+	}
+}
+
+func TestImportWebRedirection(t *testing.T) {
+	installTransport()
+	// only example.co.uk is a domain of the account, with a redirection
+	// of its www host
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if !strings.Contains(string(body), "<string>example.co.uk</string>") {
+			w.Write([]byte(testXMLRPCFaultOf(510042, "Error on object : OBJECT_DOMAIN (CAUSE_NOTFOUND) [Domain doesn't exist.]")))
+			return
+		}
+		w.Write([]byte(testXMLRPCResponse(`<array><data><value><struct>` +
+			`<member><name>host</name><value><string>www</string></value></member>` +
+			`<member><name>url</name><value><string>https://example.com/</string></value></member>` +
+			`<member><name>type</name><value><string>http301</string></value></member>` +
+			`</struct></value></data></array>`)))
+	}))
+	defer server.Close()
+	meta := &Meta{Client: &client.Client{Key: "key", Url: server.URL}, Cache: NewZoneCache()}
+
+	d := schema.TestResourceDataRaw(t, resourceWebRedirection().Schema, map[string]interface{}{})
+	d.SetId("www.example.co.uk.")
+
+	states, err := importWebRedirection(context.Background(), d, meta)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	s := states[0]
+	if s.Id() != "www.example.co.uk" || s.Get("domain").(string) != "example.co.uk" || s.Get("host").(string) != "www" {
+		t.Fatalf("expected host www of example.co.uk, got: %s %v %v", s.Id(), s.Get("domain"), s.Get("host"))
+	}
+
+	for _, id := range []string{"localhost", "blog.example.co.uk", "www.example.org"} {
+		d.SetId(id)
+		if _, err := importWebRedirection(context.Background(), d, meta); err == nil {
+			t.Fatalf("expected import ID %q to be refused", id)
+		}
+	}
+}
+
+func TestAccGandiWebRedirection(t *testing.T) {
+	domainName := os.Getenv("GANDI_DOMAIN")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckDomain(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckGandiWebRedirectionDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: fmt.Sprintf(testGandiWebRedirectionConfig, domainName, "permanent"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"gandi_web_redirection.test", "id", "tftest."+domainName),
+					resource.TestCheckResourceAttr(
+						"gandi_web_redirection.test", "type", "permanent"),
+				),
+			},
+			resource.TestStep{
+				Config: fmt.Sprintf(testGandiWebRedirectionConfig, domainName, "temporary"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"gandi_web_redirection.test", "type", "temporary"),
+				),
+			},
+			resource.TestStep{
+				ResourceName:      "gandi_web_redirection.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckGandiWebRedirectionDestroy(s *terraform.State) error {
+	c := testAccProvider.Meta().(*Meta).Client
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "gandi_web_redirection" {
+			continue
+		}
+
+		var res []map[string]interface{}
+		if err := c.Call("domain.webredir.list", []interface{}{c.Key, rs.Primary.Attributes["domain"]}, &res); err != nil {
+			return err
+		}
+		for _, redirection := range res {
+			if redirection["host"] == rs.Primary.Attributes["host"] {
+				return fmt.Errorf("Web redirection still exists")
+			}
+		}
+	}
+
+	return nil
+}
+
+const testGandiWebRedirectionConfig = `
+resource "gandi_web_redirection" "test" {
+  domain = "%s"
+  host = "tftest"
+  url = "https://example.com/"
+  type = "%s"
+}`
@@ -1,21 +1,30 @@
 package main
 
 import (
-	"fmt"
+	"context"
 	"log"
 	"strconv"
+	"time"
 
-	"github.com/hashicorp/terraform/helper/schema"
-	"github.com/prasmussen/gandi-api/client"
-	"github.com/prasmussen/gandi-api/domain/zone"
+	"github.com/bemehow/terraform-provider-gandi/gandi/domain/zone"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func resourceZone() *schema.Resource {
 	return &schema.Resource{
-		Create: CreateZone,
-		Update: UpdateZone,
-		Read:   ReadZone,
-		Delete: DeleteZone,
+		CreateContext: CreateZone,
+		UpdateContext: UpdateZone,
+		ReadContext:   ReadZone,
+		DeleteContext: DeleteZone,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
@@ -33,7 +42,7 @@ func resourceZone() *schema.Resource {
 
 // getZoneClient wraps Gandi Client in Zone Resource Methods
 func getZoneClient(meta interface{}) *zone.Zone {
-	return zone.New(meta.(*client.Client))
+	return zone.New(meta.(*Meta).Client)
 }
 
 // TODO: get a function that would return a domain name the zone is associated to
@@ -42,44 +51,47 @@ func getZoneClient(meta interface{}) *zone.Zone {
 // }
 
 // UpdateZone changes zone properties
-func UpdateZone(d *schema.ResourceData, meta interface{}) error { return nil }
+func UpdateZone(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return nil
+}
 
 // CreateZone creates new zone
-func CreateZone(d *schema.ResourceData, meta interface{}) error {
+func CreateZone(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := getZoneClient(meta)
 
 	zone, err := client.Create(d.Get("name").(string))
 	if err != nil {
-		return fmt.Errorf("Cannot create zone: %s", err)
+		return diag.FromErr(wrapAPIError(err, "Cannot create zone"))
 	}
 
 	// Assign the zone Id to a string repr of the zone.Id
 	d.SetId(strconv.FormatInt(zone.Id, 10))
+	invalidateZone(meta, zone.Id)
 	log.Printf("[INFO] Created zone with ID: %v", zone.Id)
 
 	// TODO: make the association happen here (under create) so it can be read later with ReadZone
 
-	return ReadZone(d, meta)
+	return ReadZone(ctx, d, meta)
 }
 
 // ReadZone fetches configuration
-func ReadZone(d *schema.ResourceData, meta interface{}) error {
-	client := getZoneClient(meta)
-
+func ReadZone(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	//Id is a name after the resource "type" "name"
 	log.Printf("[DEBUG] Reading zone: %v", d.Id())
 
 	// Id is stored as string in tfstate, API expects a int64
 	ID, _ := strconv.ParseInt(d.Id(), 10, 64)
 
-	// Read info about the zone
-	zone, err := client.Info(ID)
-	if err != nil {
-		// set the name to ""
-		log.Printf("[DEBUG] Unable to read zone: %s. Cleaning resource reference", err)
+	// Read info about the zone, only a missing zone is removed from the state
+	zone, err := getZoneInfo(meta, ID)
+	if isNotFound(err) {
+		log.Printf("[DEBUG] Zone %v not found. Cleaning local state reference", d.Id())
 		d.SetId("")
 		return nil
 	}
+	if err != nil {
+		return diag.FromErr(wrapAPIError(err, "Cannot read zone %v", d.Id()))
+	}
 
 	d.Set("name", zone.Name)
 	//TODO: figure out how to fetch the information which domain the zone is attached to
@@ -89,15 +101,21 @@ func ReadZone(d *schema.ResourceData, meta interface{}) error {
 }
 
 // DeleteZone deletes configuration
-func DeleteZone(d *schema.ResourceData, meta interface{}) error {
+func DeleteZone(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := getZoneClient(meta)
 
 	log.Printf("[DEBUG] Deleting zone: %v", d.Id())
 
 	ID, _ := strconv.ParseInt(d.Id(), 10, 64)
 	success, err := client.Delete(ID)
+	invalidateZone(meta, ID)
+	if isNotFound(err) {
+		log.Printf("[DEBUG] Zone %v already deleted", d.Id())
+		d.SetId("")
+		return nil
+	}
 	if err != nil {
-		return fmt.Errorf("Cannot delete zone: %s", err)
+		return diag.FromErr(wrapAPIError(err, "Cannot delete zone"))
 	}
 
 	if success {
@@ -105,5 +123,5 @@ func DeleteZone(d *schema.ResourceData, meta interface{}) error {
 		d.SetId("")
 	}
 
-	return err
+	return diag.FromErr(err)
 }
@@ -1,24 +1,34 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"net/http"
 	"strconv"
 	"testing"
 
-	"github.com/hashicorp/terraform/helper/resource"
-	"github.com/hashicorp/terraform/terraform"
-	"github.com/prasmussen/gandi-api/domain/zone"
+	"github.com/bemehow/terraform-provider-gandi/gandi/domain/zone"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
-func TestAccGandiZone(t *testing.T) {
-	// var zone zone.ZoneInfo
-
+func TestAccGandiZoneImport(t *testing.T) {
 	resource.Test(t, resource.TestCase{
-		PreCheck:     func() { testAccPreCheck(t) },
-		Providers:    testAccProviders,
-		CheckDestroy: testAccCheckGandiZoneDestroy,
-		Steps:        []resource.TestStep{},
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckGandiZoneDestroy,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testGandiZoneConfig,
+			},
+			resource.TestStep{
+				ResourceName:            "gandi_zone.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"domain_id"},
+			},
+		},
 	})
 }
 
@@ -29,8 +39,8 @@ func TestAccGandiZoneCreate(t *testing.T) {
 			testAccPreCheck(t)
 			testAccPreCheckZone(t)
 		},
-		Providers:    testAccProviders,
-		CheckDestroy: testAccCheckGandiRecordDestroy,
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckGandiRecordDestroy,
 		Steps: []resource.TestStep{
 			resource.TestStep{
 				Config: fmt.Sprintf(testGandiZoneConfig),
@@ -108,3 +118,32 @@ const testGandiZoneConfig = `
 resource "gandi_zone" "test" {
   name = "testing_zone"
 	}`
+
+func TestReadZone(t *testing.T) {
+	d := resourceZone().TestResourceData()
+	d.SetId("42")
+	found := testGandiMeta(t, http.StatusOK, testXMLRPCResponse(
+		`<struct><member><name>id</name><value><int>42</int></value></member>`+
+			`<member><name>name</name><value><string>example.com</string></value></member></struct>`))
+	if diags := ReadZone(context.Background(), d, found); diags.HasError() || d.Get("name") != "example.com" {
+		t.Fatalf("expected the zone to be read, got: %v %v", diags, d.Get("name"))
+	}
+
+	notFound := testGandiMeta(t, http.StatusOK,
+		testXMLRPCFaultOf(510042, "Error on object : OBJECT_ZONE (CAUSE_NOTFOUND) [Zone 42 doesn't exist.]"))
+	if diags := ReadZone(context.Background(), d, notFound); diags.HasError() || d.Id() != "" {
+		t.Fatalf("expected a missing zone to be removed from the state, got: %v %q", diags, d.Id())
+	}
+
+	failing := map[string]*Meta{
+		"denied": testGandiMeta(t, http.StatusOK,
+			testXMLRPCFaultOf(510150, "Error on object : OBJECT_ZONE (CAUSE_NORIGHT) [Access denied]")),
+		"server error": testGandiMeta(t, http.StatusInternalServerError, "Internal Server Error"),
+	}
+	for name, meta := range failing {
+		d.SetId("42")
+		if diags := ReadZone(context.Background(), d, meta); !diags.HasError() || d.Id() != "42" {
+			t.Fatalf("%s: expected an error and the zone kept in the state, got: %v %q", name, diags, d.Id())
+		}
+	}
+}
@@ -1,23 +1,32 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strconv"
 	"strings"
+	"time"
 
+	zoneVersion "github.com/bemehow/terraform-provider-gandi/gandi/domain/zone/version"
 	"github.com/cznic/sortutil"
-	"github.com/hashicorp/terraform/helper/schema"
-	"github.com/prasmussen/gandi-api/client"
-	zoneVersion "github.com/prasmussen/gandi-api/domain/zone/version"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func resourceZoneVersion() *schema.Resource {
 	return &schema.Resource{
-		Create: CreateZoneVersion,
-		Update: UpdateZoneVersion,
-		Read:   ReadZoneVersion,
-		Delete: DeleteZoneVersion,
+		CreateContext: CreateZoneVersion,
+		UpdateContext: UpdateZoneVersion,
+		ReadContext:   ReadZoneVersion,
+		DeleteContext: DeleteZoneVersion,
+		// creating a version copies every record of the base version, which
+		// takes a while on large zones
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"zone_id": &schema.Schema{
@@ -40,11 +49,11 @@ func resourceZoneVersion() *schema.Resource {
 
 // getZoneVersionClient wraps Gandi Client in Zone Resource Methods
 func getZoneVersionClient(meta interface{}) *zoneVersion.Version {
-	return zoneVersion.New(meta.(*client.Client))
+	return zoneVersion.New(meta.(*Meta).Client)
 }
 
 // UpdateZoneVersion changes zone properties
-func UpdateZoneVersion(d *schema.ResourceData, meta interface{}) error {
+func UpdateZoneVersion(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	// Updates to the zone versions are theoretically possible but they involve
 	// change to the ID since the version information is not available
 	return nil
@@ -54,7 +63,7 @@ func UpdateZoneVersion(d *schema.ResourceData, meta interface{}) error {
 func createZoneVersion(client *zoneVersion.Version, zoneID int64, baseVersion int64, zoneVersion int64) (string, error) {
 	zoneExist, err := CheckZoneVersion(client, zoneID, zoneVersion)
 	if err != nil {
-		return "", fmt.Errorf("Cannot check zone versions: %v", err)
+		return "", wrapAPIError(err, "Cannot check zone versions")
 	}
 
 	if zoneExist {
@@ -65,7 +74,7 @@ func createZoneVersion(client *zoneVersion.Version, zoneID int64, baseVersion in
 	newZoneVersion, err := client.New(zoneID, baseVersion)
 
 	if err != nil {
-		return "", fmt.Errorf("Cannot create zone version: %s", err)
+		return "", wrapAPIError(err, "Cannot create zone version")
 	}
 
 	// Id is stored as compound string "zoneID_zoneVersion"
@@ -75,7 +84,7 @@ func createZoneVersion(client *zoneVersion.Version, zoneID int64, baseVersion in
 }
 
 // CreateZoneVersion creates new zone
-func CreateZoneVersion(d *schema.ResourceData, meta interface{}) error {
+func CreateZoneVersion(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := getZoneVersionClient(meta)
 
 	baseVersion, _ := strconv.ParseInt(d.Get("base_version").(string), 10, 64)
@@ -83,8 +92,9 @@ func CreateZoneVersion(d *schema.ResourceData, meta interface{}) error {
 	zoneVersion, _ := strconv.ParseInt(d.Get("zone_version").(string), 10, 64)
 
 	ID, err := createZoneVersion(client, zoneID, baseVersion, zoneVersion)
+	invalidateZone(meta, zoneID)
 	if err != nil {
-		return fmt.Errorf("Could not create zone version: %v", err)
+		return diag.FromErr(wrapAPIError(err, "Could not create zone version"))
 	}
 
 	// ID of the resource includes the version with format: ZONEID_VERSION
@@ -94,7 +104,7 @@ func CreateZoneVersion(d *schema.ResourceData, meta interface{}) error {
 	d.SetId(ID)
 	log.Printf("[INFO] Created new zone version with ID: %v", ID)
 
-	return ReadZoneVersion(d, meta)
+	return ReadZoneVersion(ctx, d, meta)
 }
 
 // decode zoneID and version from the resource ID
@@ -113,7 +123,7 @@ func CheckZoneVersion(client *zoneVersion.Version, zoneID int64, zoneVersionNumb
 	versions, err := client.List(zoneID)
 
 	if err != nil {
-		return false, fmt.Errorf("Cannot read zone version from: %v: %s", zoneID, err.Error())
+		return false, wrapAPIError(err, "Cannot read zone version from: %v", zoneID)
 	}
 
 	for _, v := range versions {
@@ -133,15 +143,21 @@ func CheckZoneVersion(client *zoneVersion.Version, zoneID int64, zoneVersionNumb
 }
 
 // ReadZoneVersion validates if the zone with the specified ID (version) exist
-func ReadZoneVersion(d *schema.ResourceData, meta interface{}) error {
+func ReadZoneVersion(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := getZoneVersionClient(meta)
 
 	// Parse out version numbers from the resource ID
 	zoneID, zoneVersion := resourceIDSplit(d.Id(), "_")
 
 	zoneExists, err := CheckZoneVersion(client, zoneID, zoneVersion)
+	if isNotFound(err) {
+		// the zone is gone and its versions with it
+		log.Printf("[DEBUG] Zone of version %v not found. Cleaning local state reference", d.Id())
+		d.SetId("")
+		return nil
+	}
 	if err != nil {
-		return fmt.Errorf("Cannot verify if zone version exist: %v", err)
+		return diag.FromErr(wrapAPIError(err, "Cannot verify if zone version exist"))
 	}
 
 	if !zoneExists {
@@ -153,7 +169,7 @@ func ReadZoneVersion(d *schema.ResourceData, meta interface{}) error {
 }
 
 // DeleteZone deletes configuration
-func DeleteZoneVersion(d *schema.ResourceData, meta interface{}) error {
+func DeleteZoneVersion(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := getZoneVersionClient(meta)
 
 	log.Printf("[DEBUG] Deleting zone version: %v", d.Id())
@@ -162,8 +178,14 @@ func DeleteZoneVersion(d *schema.ResourceData, meta interface{}) error {
 
 	log.Printf("[DEBUG] Deleting zone version: %v", d.Id())
 	success, err := client.Delete(zoneID, zoneVersion)
+	invalidateZone(meta, zoneID)
+	if isNotFound(err) {
+		log.Printf("[DEBUG] Zone version %v already deleted", d.Id())
+		d.SetId("")
+		return nil
+	}
 	if err != nil {
-		return fmt.Errorf("Cannot delete zone version: %v", err)
+		return diag.FromErr(wrapAPIError(err, "Cannot delete zone version"))
 	}
 
 	if success {
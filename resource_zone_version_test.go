@@ -1,12 +1,14 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"testing"
 
-	"github.com/hashicorp/terraform/helper/resource"
-	"github.com/hashicorp/terraform/terraform"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 func TestAccGandiZoneVersion(t *testing.T) {
@@ -17,8 +19,8 @@ func TestAccGandiZoneVersion(t *testing.T) {
 			testAccPreCheck(t)
 			testAccPreCheckZoneVersion(t)
 		},
-		Providers:    testAccProviders,
-		CheckDestroy: testAccCheckGandiRecordDestroy,
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckGandiRecordDestroy,
 		Steps: []resource.TestStep{
 			resource.TestStep{
 				Config: fmt.Sprintf(testGandiZoneVersionConfig, zoneID),
@@ -89,3 +91,38 @@ resource "gandi_zone_version" "test" {
 	zone_version = 3
 	zone_id = "%s"
 }`
+
+func TestReadZoneVersion(t *testing.T) {
+	d := resourceZoneVersion().TestResourceData()
+	versions := testGandiMeta(t, http.StatusOK, testXMLRPCResponse(
+		`<array><data><value><struct><member><name>id</name><value><int>1</int></value></member></struct></value>`+
+			`<value><struct><member><name>id</name><value><int>2</int></value></member></struct></value></data></array>`))
+
+	d.SetId("42_2")
+	if diags := ReadZoneVersion(context.Background(), d, versions); diags.HasError() || d.Id() != "42_2" {
+		t.Fatalf("expected the version to be found, got: %v %q", diags, d.Id())
+	}
+	d.SetId("42_3")
+	if diags := ReadZoneVersion(context.Background(), d, versions); diags.HasError() || d.Id() != "" {
+		t.Fatalf("expected a missing version to be removed from the state, got: %v %q", diags, d.Id())
+	}
+
+	notFound := testGandiMeta(t, http.StatusOK,
+		testXMLRPCFaultOf(510042, "Error on object : OBJECT_ZONE (CAUSE_NOTFOUND) [Zone 42 doesn't exist.]"))
+	d.SetId("42_2")
+	if diags := ReadZoneVersion(context.Background(), d, notFound); diags.HasError() || d.Id() != "" {
+		t.Fatalf("expected the version of a missing zone to be removed from the state, got: %v %q", diags, d.Id())
+	}
+
+	failing := map[string]*Meta{
+		"denied": testGandiMeta(t, http.StatusOK,
+			testXMLRPCFaultOf(510150, "Error on object : OBJECT_ZONE (CAUSE_NORIGHT) [Access denied]")),
+		"server error": testGandiMeta(t, http.StatusInternalServerError, "Internal Server Error"),
+	}
+	for name, meta := range failing {
+		d.SetId("42_2")
+		if diags := ReadZoneVersion(context.Background(), d, meta); !diags.HasError() || d.Id() != "42_2" {
+			t.Fatalf("%s: expected an error and the version kept in the state, got: %v %q", name, diags, d.Id())
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// xmlrpcNode is an element of an XML-RPC document
+type xmlrpcNode struct {
+	XMLName xml.Name
+	Content string       `xml:",chardata"`
+	Nodes   []xmlrpcNode `xml:",any"`
+}
+
+func (n *xmlrpcNode) child(name string) *xmlrpcNode {
+	for i := range n.Nodes {
+		if n.Nodes[i].XMLName.Local == name {
+			return &n.Nodes[i]
+		}
+	}
+	return nil
+}
+
+// isSecretMember reports whether a struct member holds credentials, the
+// same members redactXMLRPC hides in the TRACE dumps
+func isSecretMember(name string) bool {
+	switch name {
+	case "password", "authinfo", "auth_info":
+		return true
+	}
+	return false
+}
+
+// decode converts a <value> element, redacting secrets
+func (n *xmlrpcNode) decode() interface{} {
+	if len(n.Nodes) == 0 {
+		return logSecrets.Redact(n.Content)
+	}
+
+	v := &n.Nodes[0]
+	content := strings.TrimSpace(v.Content)
+	switch v.XMLName.Local {
+	case "int", "i4", "i8":
+		i, _ := strconv.ParseInt(content, 10, 64)
+		return i
+	case "boolean":
+		return content == "1"
+	case "double":
+		f, _ := strconv.ParseFloat(content, 64)
+		return f
+	case "nil":
+		return nil
+	case "array":
+		values := []interface{}{}
+		if data := v.child("data"); data != nil {
+			for i := range data.Nodes {
+				values = append(values, data.Nodes[i].decode())
+			}
+		}
+		return values
+	case "struct":
+		members := make(map[string]interface{})
+		for _, m := range v.Nodes {
+			name := m.child("name")
+			value := m.child("value")
+			if name == nil || value == nil {
+				continue
+			}
+			if isSecretMember(name.Content) {
+				members[name.Content] = redactedValue
+			} else {
+				members[name.Content] = value.decode()
+			}
+		}
+		return members
+	default:
+		// string, dateTime.iso8601 and base64 are kept as text
+		return logSecrets.Redact(v.Content)
+	}
+}
+
+// decodeXMLRPCParams returns the parameters of a call or response
+func decodeXMLRPCParams(root *xmlrpcNode) []interface{} {
+	params := []interface{}{}
+	if p := root.child("params"); p != nil {
+		for _, param := range p.Nodes {
+			if value := param.child("value"); value != nil {
+				params = append(params, value.decode())
+			}
+		}
+	}
+	return params
+}
+
+// TraceEntry describes one XML-RPC call
+type TraceEntry struct {
+	Time        time.Time     `json:"time"`
+	Method      string        `json:"method"`
+	Params      []interface{} `json:"params"`
+	DurationMs  float64       `json:"duration_ms"`
+	FaultCode   int64         `json:"fault_code,omitempty"`
+	FaultString string        `json:"fault_string,omitempty"`
+	Response    interface{}   `json:"response,omitempty"`
+	Error       string        `json:"error,omitempty"`
+}
+
+// NewTraceEntry decodes the request and response of a call, response is
+// empty and err set when the call did not get an answer
+func NewTraceEntry(start time.Time, duration time.Duration, request string, response string, err error) *TraceEntry {
+	e := &TraceEntry{
+		Time:       start,
+		Method:     xmlrpcMethod(request),
+		Params:     []interface{}{},
+		DurationMs: float64(duration) / float64(time.Millisecond),
+	}
+
+	var call xmlrpcNode
+	if xml.Unmarshal([]byte(request), &call) == nil {
+		e.Params = decodeXMLRPCParams(&call)
+	}
+
+	if err != nil {
+		e.Error = logSecrets.Redact(err.Error())
+		return e
+	}
+
+	var resp xmlrpcNode
+	if err := xml.Unmarshal([]byte(response), &resp); err != nil {
+		e.Error = fmt.Sprintf("Cannot decode response: %v", err)
+		return e
+	}
+
+	if fault := resp.child("fault"); fault != nil {
+		if value := fault.child("value"); value != nil {
+			members, _ := value.decode().(map[string]interface{})
+			e.FaultCode, _ = members["faultCode"].(int64)
+			e.FaultString, _ = members["faultString"].(string)
+		}
+		return e
+	}
+
+	if params := decodeXMLRPCParams(&resp); len(params) > 0 {
+		e.Response = params[0]
+	}
+	return e
+}
+
+// Summary describes the call on one line, without the response
+func (e *TraceEntry) Summary() string {
+	params, _ := json.Marshal(e.Params)
+	result := "ok"
+	switch {
+	case e.Error != "":
+		result = "error: " + e.Error
+	case e.FaultCode != 0 || e.FaultString != "":
+		result = fmt.Sprintf("fault %d: %s", e.FaultCode, e.FaultString)
+	}
+	return fmt.Sprintf("%s%s -> %s in %.1fms", e.Method, params, result, e.DurationMs)
+}
+
+// Tracer logs the XML-RPC calls and appends them as JSON lines to a file
+type Tracer struct {
+	sync.Mutex
+	enabled bool
+	file    *os.File
+}
+
+// apiTrace traces the calls going through gandiTransport when enabled
+var apiTrace = &Tracer{}
+
+// Configure enables tracing, path receives the full trace when set
+func (t *Tracer) Configure(enabled bool, path string) error {
+	t.Lock()
+	defer t.Unlock()
+
+	if path != "" && t.file == nil {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+		if err != nil {
+			return fmt.Errorf("Cannot open trace file: %v", err)
+		}
+		t.file = f
+	}
+	t.enabled = t.enabled || enabled || path != ""
+
+	return nil
+}
+
+// Enabled reports whether calls are traced
+func (t *Tracer) Enabled() bool {
+	t.Lock()
+	defer t.Unlock()
+	return t.enabled
+}
+
+// Trace records a call
+func (t *Tracer) Trace(e *TraceEntry) {
+	log.Printf("[DEBUG] Gandi API call %s", e.Summary())
+
+	t.Lock()
+	defer t.Unlock()
+	if t.file == nil {
+		return
+	}
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		log.Printf("[WARN] Cannot encode trace of %s: %v", e.Method, err)
+		return
+	}
+	if _, err := t.file.Write(append(line, '\n')); err != nil {
+		log.Printf("[WARN] Cannot write trace of %s: %v", e.Method, err)
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testTraceKey = "trace-test-api-key"
+
+var testTraceRequest = `<?xml version="1.0"?>
+<methodCall><methodName>domain.mailbox.create</methodName><params>
+<param><value><string>` + testTraceKey + `</string></value></param>
+<param><value><string>example.com</string></value></param>
+<param><value>admin</value></param>
+<param><value><struct>
+<member><name>password</name><value><string>hunter2</string></value></member>
+<member><name>quota</name><value><int>512</int></value></member>
+<member><name>aliases</name><value><array><data><value><string>root</string></value></data></array></value></member>
+</struct></value></param>
+</params></methodCall>`
+
+const testTraceResponse = `<?xml version="1.0"?>
+<methodResponse><params><param><value><struct>
+<member><name>login</name><value><string>admin</string></value></member>
+<member><name>responder</name><value><boolean>0</boolean></value></member>
+</struct></value></param></params></methodResponse>`
+
+func TestTraceEntry(t *testing.T) {
+	logSecrets.Register(testTraceKey)
+
+	e := NewTraceEntry(time.Now(), 1500*time.Microsecond, testTraceRequest, testTraceResponse, nil)
+
+	if e.Method != "domain.mailbox.create" {
+		t.Fatalf("expected domain.mailbox.create, got %s", e.Method)
+	}
+	params, _ := json.Marshal(e.Params)
+	expected := `["[redacted]","example.com","admin",{"aliases":["root"],"password":"[redacted]","quota":512}]`
+	if string(params) != expected {
+		t.Fatalf("expected params %s, got %s", expected, params)
+	}
+
+	response, _ := json.Marshal(e.Response)
+	if string(response) != `{"login":"admin","responder":false}` {
+		t.Fatalf("unexpected response: %s", response)
+	}
+
+	summary := e.Summary()
+	if !strings.HasSuffix(summary, "-> ok in 1.5ms") || strings.Contains(summary, "hunter2") {
+		t.Fatalf("unexpected summary: %s", summary)
+	}
+}
+
+func TestTraceEntryFault(t *testing.T) {
+	e := NewTraceEntry(time.Now(), time.Millisecond, testTraceRequest, testXMLRPCFault, nil)
+
+	if e.FaultCode != 510042 || !strings.Contains(e.FaultString, "CAUSE_NOTFOUND") {
+		t.Fatalf("unexpected fault: %d %s", e.FaultCode, e.FaultString)
+	}
+	if !strings.Contains(e.Summary(), "-> fault 510042: Error on object") {
+		t.Fatalf("unexpected summary: %s", e.Summary())
+	}
+
+	e = NewTraceEntry(time.Now(), time.Millisecond, testTraceRequest, "", fmt.Errorf("connection refused"))
+	if e.Error != "connection refused" {
+		t.Fatalf("expected the transport error, got %q", e.Error)
+	}
+}
+
+func TestTracerFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace.jsonl")
+
+	tracer := &Tracer{}
+	if tracer.Enabled() {
+		t.Fatalf("expected tracing to be disabled by default")
+	}
+	if err := tracer.Configure(false, path); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if !tracer.Enabled() {
+		t.Fatalf("expected a trace file to enable tracing")
+	}
+
+	tracer.Trace(NewTraceEntry(time.Now(), time.Millisecond, testTraceRequest, testTraceResponse, nil))
+	tracer.Trace(NewTraceEntry(time.Now(), time.Millisecond, testTraceRequest, testXMLRPCFault, nil))
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 trace lines, got %d", len(lines))
+	}
+
+	var e TraceEntry
+	if err := json.Unmarshal([]byte(lines[1]), &e); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if e.FaultCode != 510042 || strings.Contains(string(data), "hunter2") {
+		t.Fatalf("unexpected trace: %s", lines[1])
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bemehow/terraform-provider-gandi/gandi/client"
+	"github.com/kolo/xmlrpc"
+)
+
+// defaultAPITimeout bounds each XML-RPC call, so a hung one fails long
+// before the resource timeouts
+const defaultAPITimeout = "1m"
+
+// gandiTransport wraps the HTTP transport carrying XML-RPC calls to Gandi,
+// dumping them at TRACE level, counting them in apiMetrics and tracing them
+// with apiTrace
+type gandiTransport struct {
+	next http.RoundTripper
+}
+
+func (t *gandiTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req, cancel, err := boundRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	// the body is read before returning, so the call can end here
+	defer cancel()
+
+	var request string
+	if req.Body != nil {
+		body, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		request = string(body)
+		log.Printf("[TRACE] XML-RPC request to %s: %s", req.URL, redactXMLRPC(request))
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		t.done(start, request, "", err)
+		return nil, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		t.done(start, request, "", err)
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		// the XML-RPC client would only report a bad status code
+		err := newRateLimited(resp.Status)
+		t.done(start, request, "", err)
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	log.Printf("[TRACE] XML-RPC response %s: %s", resp.Status, redactXMLRPC(string(body)))
+	t.done(start, request, string(body), nil)
+
+	// the XML-RPC client turns faults into plain strings, returned from here
+	// they keep their code for parseGandiFault
+	if fault, ok := xmlrpc.Response(body).Err().(xmlrpc.FaultError); ok {
+		return nil, fault
+	}
+
+	return resp, nil
+}
+
+// done counts a call in apiMetrics and traces it when tracing is enabled
+func (t *gandiTransport) done(start time.Time, request string, response string, err error) {
+	duration := time.Since(start)
+	apiMetrics.Record(xmlrpcMethod(request), duration, err == nil && isXMLRPCFault(response), err != nil)
+
+	if apiTrace.Enabled() {
+		apiTrace.Trace(NewTraceEntry(start, duration, request, response, err))
+	}
+}
+
+var installTransportOnce sync.Once
+
+// installTransport routes the XML-RPC calls through gandiTransport. The
+// gandi client creates its XML-RPC client with the default transport,
+// so that is the one being wrapped.
+func installTransport() {
+	installTransportOnce.Do(func() {
+		http.DefaultTransport = &gandiTransport{next: http.DefaultTransport}
+	})
+}
+
+// boundContexts maps the URL fragments of the clients returned by bindClient
+// to the calls they are bound to
+var (
+	boundContexts  sync.Map
+	boundContextID int64
+)
+
+// boundCall is the context calls of a bound client are made with, each of
+// them lasting at most timeout when it is set
+type boundCall struct {
+	ctx     context.Context
+	timeout time.Duration
+}
+
+// bindClient returns a copy of c whose calls are cancelled with ctx, and
+// after timeout when it is set. The gandi client takes no context, so
+// the copy carries an URL fragment, never sent to Gandi, that
+// gandiTransport maps back to ctx.
+func bindClient(ctx context.Context, c *client.Client, timeout time.Duration) *client.Client {
+	var id string
+	if ctx.Done() == nil {
+		if timeout == 0 {
+			// never cancelled
+			return c
+		}
+		// only the timeout applies, clients bound to it share the entry
+		id = "timeout-" + timeout.String()
+		boundContexts.Store(id, boundCall{ctx: context.Background(), timeout: timeout})
+	} else {
+		id = strconv.FormatInt(atomic.AddInt64(&boundContextID, 1), 10)
+		boundContexts.Store(id, boundCall{ctx: ctx, timeout: timeout})
+		context.AfterFunc(ctx, func() { boundContexts.Delete(id) })
+	}
+
+	bound := *c
+	bound.Url = strings.SplitN(c.Url, "#", 2)[0] + "#" + id
+	return &bound
+}
+
+// boundRequest attaches the context of a client returned by bindClient to
+// its request, the returned func releases it once the call is done. A
+// client whose context ended has no entry left, its calls fail with
+// context.Canceled instead of being sent without cancellation nor timeout.
+func boundRequest(req *http.Request) (*http.Request, context.CancelFunc, error) {
+	if req.URL.Fragment == "" {
+		return req, func() {}, nil
+	}
+
+	bound, ok := boundContexts.Load(req.URL.Fragment)
+	if !ok {
+		return nil, nil, context.Canceled
+	}
+
+	call := bound.(boundCall)
+	ctx, cancel := call.ctx, context.CancelFunc(func() {})
+	if call.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, call.timeout)
+	}
+	req = req.Clone(ctx)
+	req.URL.Fragment = ""
+	return req, cancel, nil
+}
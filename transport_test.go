@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bemehow/terraform-provider-gandi/gandi/client"
+)
+
+// hungServer never answers before the test ends
+func hungServer(t *testing.T) *httptest.Server {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	// cleanups run last first, the handlers return before Close waits for them
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(done) })
+	return server
+}
+
+// callBound posts an XML-RPC call to the URL of a bound client
+func callBound(c *client.Client) error {
+	transport := &gandiTransport{next: &http.Transport{}}
+	body := `<?xml version="1.0"?><methodCall><methodName>domain.info</methodName></methodCall>`
+	req, _ := http.NewRequest("POST", c.Url, strings.NewReader(body))
+	resp, err := transport.RoundTrip(req)
+	if err == nil {
+		resp.Body.Close()
+	}
+	return err
+}
+
+func TestBoundClientAPITimeout(t *testing.T) {
+	server := hungServer(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c := bindClient(ctx, &client.Client{Url: server.URL}, 100*time.Millisecond)
+
+	start := time.Now()
+	if err := callBound(c); err == nil || !strings.Contains(err.Error(), "deadline exceeded") {
+		t.Fatalf("expected the call to time out, got: %v", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Fatalf("expected the call to fail fast, took %s", time.Since(start))
+	}
+}
+
+func TestBoundClientTimeoutOnly(t *testing.T) {
+	server := hungServer(t)
+	c := &client.Client{Url: server.URL}
+
+	if bound := bindClient(context.Background(), c, 0); bound != c {
+		t.Fatalf("expected a client without timeout nor cancellation to stay unbound")
+	}
+
+	start := time.Now()
+	bound := bindClient(context.Background(), c, 100*time.Millisecond)
+	if err := callBound(bound); err == nil || !strings.Contains(err.Error(), "deadline exceeded") {
+		t.Fatalf("expected the call to time out, got: %v", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Fatalf("expected the call to fail fast, took %s", time.Since(start))
+	}
+}
+
+func TestBoundClientCancelled(t *testing.T) {
+	server := hungServer(t)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	c := bindClient(ctx, &client.Client{Url: server.URL}, 0)
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	if err := callBound(c); err == nil || !strings.Contains(err.Error(), "canceled") {
+		t.Fatalf("expected the call to be cancelled, got: %v", err)
+	}
+}
+
+func TestBoundClientAfterCancel(t *testing.T) {
+	server := hungServer(t)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	c := bindClient(ctx, &client.Client{Url: server.URL}, 0)
+	cancel()
+
+	// the entry is dropped once the context ended, a later call must not
+	// go out unbounded
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, ok := boundContexts.Load(strings.SplitN(c.Url, "#", 2)[1]); !ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the bound context to be dropped")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	start := time.Now()
+	if err := callBound(c); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the call to be cancelled, got: %v", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Fatalf("expected the call to fail fast, took %s", time.Since(start))
+	}
+}
+
+// testXMLRPCResponse wraps the XML-RPC value returned by a test server
+func testXMLRPCResponse(value string) string {
+	return `<?xml version="1.0"?><methodResponse><params><param><value>` + value +
+		`</value></param></params></methodResponse>`
+}
+
+// testXMLRPCFaultOf is a Gandi fault returned by a test server
+func testXMLRPCFaultOf(code int, message string) string {
+	return fmt.Sprintf(`<?xml version="1.0"?><methodResponse><fault><value><struct>`+
+		`<member><name>faultCode</name><value><int>%d</int></value></member>`+
+		`<member><name>faultString</name><value><string>%s</string></value></member>`+
+		`</struct></value></fault></methodResponse>`, code, message)
+}
+
+// testGandiMeta returns a provider meta whose calls are all answered with
+// status and body by a test server, through gandiTransport like
+// Config.Client
+func testGandiMeta(t *testing.T, status int, body string) *Meta {
+	installTransport()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	return &Meta{
+		Client: &client.Client{Key: "key", Url: server.URL},
+		Cache:  NewZoneCache(),
+	}
+}
+
+// testGandiMethods returns a provider meta whose calls are answered by
+// method with the bodies of responses, or with a fault for other methods.
+// The methods called are appended to calls.
+func testGandiMethods(t *testing.T, responses map[string]string, calls *[]string) *Meta {
+	installTransport()
+	var lock sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		method := xmlrpcMethod(string(body))
+
+		lock.Lock()
+		*calls = append(*calls, method)
+		lock.Unlock()
+
+		response, ok := responses[method]
+		if !ok {
+			response = testXMLRPCFaultOf(500000, "Error on object : OBJECT_UNKNOWN (CAUSE_UNKNOWN) [unexpected call]")
+		}
+		w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+
+	return &Meta{
+		Client: &client.Client{Key: "key", Url: server.URL},
+		Cache:  NewZoneCache(),
+	}
+}
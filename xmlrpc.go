@@ -0,0 +1,16 @@
+package main
+
+// Helpers decoding the untyped results of XML-RPC methods that the gandi
+// client does not wrap
+
+// toStringList converts an XML-RPC array of strings
+func toStringList(v interface{}) []string {
+	items, _ := v.([]interface{})
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"log"
+	"sync"
+
+	"github.com/bemehow/terraform-provider-gandi/gandi/domain/zone"
+	"github.com/bemehow/terraform-provider-gandi/gandi/domain/zone/record"
+)
+
+// zoneInfoVersion keys the zone info in the cache, next to the record lists
+// of its versions
+const zoneInfoVersion = -1
+
+type zoneCacheKey struct {
+	Zone    int64
+	Version int64
+}
+
+// zoneCacheEntry is filled once, concurrent readers wait for ready
+type zoneCacheEntry struct {
+	ready   chan struct{}
+	zone    *zone.ZoneInfo
+	records []*record.RecordInfo
+	byID    map[int64]*record.RecordInfo
+	err     error
+}
+
+// ZoneCache keeps zone info and record lists for the lifetime of the
+// provider, so refreshing the records of a zone costs one List call per
+// zone version instead of one per record. Writes to a zone must invalidate
+// it. Cached values are shared and must not be modified. A nil cache
+// always calls the API.
+type ZoneCache struct {
+	sync.Mutex
+	entries map[zoneCacheKey]*zoneCacheEntry
+}
+
+// NewZoneCache returns an empty cache
+func NewZoneCache() *ZoneCache {
+	return &ZoneCache{entries: make(map[zoneCacheKey]*zoneCacheEntry)}
+}
+
+// entry returns the entry for key, filling it unless another caller did or
+// is doing it. Failures are not cached.
+func (c *ZoneCache) entry(key zoneCacheKey, fill func(*zoneCacheEntry)) *zoneCacheEntry {
+	c.Lock()
+	e, ok := c.entries[key]
+	if !ok {
+		e = &zoneCacheEntry{ready: make(chan struct{})}
+		c.entries[key] = e
+	}
+	c.Unlock()
+
+	if ok {
+		<-e.ready
+		return e
+	}
+
+	fill(e)
+	if e.err != nil {
+		c.Lock()
+		if c.entries[key] == e {
+			delete(c.entries, key)
+		}
+		c.Unlock()
+	}
+	close(e.ready)
+
+	return e
+}
+
+// Zone returns the info of a zone, read with info on a miss
+func (c *ZoneCache) Zone(zoneID int64, info func(int64) (*zone.ZoneInfo, error)) (*zone.ZoneInfo, error) {
+	if c == nil {
+		return info(zoneID)
+	}
+
+	e := c.entry(zoneCacheKey{zoneID, zoneInfoVersion}, func(e *zoneCacheEntry) {
+		log.Printf("[DEBUG] Caching info of zone: %v", zoneID)
+		e.zone, e.err = info(zoneID)
+	})
+	return e.zone, e.err
+}
+
+// Records returns the records of a zone version, read with list on a miss
+func (c *ZoneCache) Records(zoneID int64, version int64,
+	list func(int64, int64) ([]*record.RecordInfo, error)) ([]*record.RecordInfo, error) {
+	e, err := c.records(zoneID, version, list)
+	if err != nil {
+		return nil, err
+	}
+	return e.records, nil
+}
+
+// Record returns a record of a zone version by ID, nil if there is none
+func (c *ZoneCache) Record(zoneID int64, version int64, recordID int64,
+	list func(int64, int64) ([]*record.RecordInfo, error)) (*record.RecordInfo, error) {
+	e, err := c.records(zoneID, version, list)
+	if err != nil {
+		return nil, err
+	}
+	return e.byID[recordID], nil
+}
+
+func (c *ZoneCache) records(zoneID int64, version int64,
+	list func(int64, int64) ([]*record.RecordInfo, error)) (*zoneCacheEntry, error) {
+	fill := func(e *zoneCacheEntry) {
+		log.Printf("[DEBUG] Caching records of zone: %v version: %v", zoneID, version)
+		e.records, e.err = list(zoneID, version)
+		e.byID = make(map[int64]*record.RecordInfo, len(e.records))
+		for _, r := range e.records {
+			e.byID[r.Id] = r
+		}
+	}
+
+	if c == nil {
+		e := &zoneCacheEntry{}
+		fill(e)
+		return e, e.err
+	}
+
+	e := c.entry(zoneCacheKey{zoneID, version}, fill)
+	return e, e.err
+}
+
+// Invalidate drops the info and every record list of a zone
+func (c *ZoneCache) Invalidate(zoneID int64) {
+	if c == nil {
+		return
+	}
+
+	c.Lock()
+	defer c.Unlock()
+	for key := range c.entries {
+		if key.Zone == zoneID {
+			delete(c.entries, key)
+		}
+	}
+	log.Printf("[DEBUG] Invalidated cache of zone: %v", zoneID)
+}
+
+// getZoneInfo returns the info of a zone through the provider cache
+func getZoneInfo(meta interface{}, zoneID int64) (*zone.ZoneInfo, error) {
+	return meta.(*Meta).Cache.Zone(zoneID, getZoneClient(meta).Info)
+}
+
+// listRecords returns the records of a zone version through the provider
+// cache
+func listRecords(meta interface{}, zoneID int64, version int64) ([]*record.RecordInfo, error) {
+	return meta.(*Meta).Cache.Records(zoneID, version, getRecordClient(meta).List)
+}
+
+// invalidateZone drops a zone from the provider cache after a write
+func invalidateZone(meta interface{}, zoneID int64) {
+	meta.(*Meta).Cache.Invalidate(zoneID)
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/bemehow/terraform-provider-gandi/gandi/domain/zone"
+	"github.com/bemehow/terraform-provider-gandi/gandi/domain/zone/record"
+)
+
+// countingList serves two records per zone version and counts the calls
+type countingList struct {
+	calls int32
+	fail  bool
+}
+
+func (l *countingList) List(zoneID int64, version int64) ([]*record.RecordInfo, error) {
+	atomic.AddInt32(&l.calls, 1)
+	if l.fail {
+		return nil, fmt.Errorf("unavailable")
+	}
+	return []*record.RecordInfo{
+		&record.RecordInfo{Id: version*10 + 1, Name: "www", Type: "A", Value: "1.1.1.1"},
+		&record.RecordInfo{Id: version*10 + 2, Name: "mail", Type: "A", Value: "2.2.2.2"},
+	}, nil
+}
+
+func TestZoneCacheRecords(t *testing.T) {
+	cache := NewZoneCache()
+	list := &countingList{}
+
+	for i := 0; i < 3; i++ {
+		r, err := cache.Record(1, 4, 42, list.List)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if r == nil || r.Name == "" {
+			t.Fatalf("expected record 42, got %#v", r)
+		}
+	}
+	if r, _ := cache.Record(1, 4, 99, list.List); r != nil {
+		t.Fatalf("expected no record 99, got %#v", r)
+	}
+	if list.calls != 1 {
+		t.Fatalf("expected 1 List call, got %d", list.calls)
+	}
+
+	// other versions are listed separately
+	if r, _ := cache.Record(1, 5, 51, list.List); r == nil {
+		t.Fatalf("expected record 51 in version 5")
+	}
+	if list.calls != 2 {
+		t.Fatalf("expected 2 List calls, got %d", list.calls)
+	}
+}
+
+func TestZoneCacheInvalidate(t *testing.T) {
+	cache := NewZoneCache()
+	list := &countingList{}
+	infoCalls := 0
+	info := func(zoneID int64) (*zone.ZoneInfo, error) {
+		infoCalls++
+		return &zone.ZoneInfo{ZoneInfoBase: &zone.ZoneInfoBase{Id: zoneID, Version: 4}}, nil
+	}
+
+	cache.Zone(1, info)
+	cache.Records(1, 4, list.List)
+	cache.Records(2, 4, list.List)
+
+	cache.Invalidate(1)
+
+	cache.Zone(1, info)
+	cache.Records(1, 4, list.List)
+	cache.Records(2, 4, list.List)
+
+	if infoCalls != 2 {
+		t.Fatalf("expected 2 Info calls, got %d", infoCalls)
+	}
+	// zone 2 was kept
+	if list.calls != 3 {
+		t.Fatalf("expected 3 List calls, got %d", list.calls)
+	}
+}
+
+func TestZoneCacheErrorsNotCached(t *testing.T) {
+	cache := NewZoneCache()
+	list := &countingList{fail: true}
+
+	if _, err := cache.Records(1, 4, list.List); err == nil {
+		t.Fatalf("expected the List error")
+	}
+
+	list.fail = false
+	records, err := cache.Records(1, 4, list.List)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(records))
+	}
+}
+
+func TestZoneCacheConcurrentReads(t *testing.T) {
+	cache := NewZoneCache()
+	list := &countingList{}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if r, err := cache.Record(1, 4, 41, list.List); err != nil || r == nil {
+				t.Errorf("expected record 41, got %#v: %v", r, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if list.calls != 1 {
+		t.Fatalf("expected 1 List call, got %d", list.calls)
+	}
+}
+
+func TestZoneCacheNil(t *testing.T) {
+	var cache *ZoneCache
+	list := &countingList{}
+
+	cache.Record(1, 4, 41, list.List)
+	cache.Record(1, 4, 41, list.List)
+	cache.Invalidate(1)
+
+	if list.calls != 2 {
+		t.Fatalf("expected every lookup to list records, got %d calls", list.calls)
+	}
+}
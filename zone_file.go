@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bemehow/terraform-provider-gandi/gandi/domain/zone/record"
+)
+
+// maxCharacterString is the longest string a TXT record can hold in one chunk
+const maxCharacterString = 255
+
+// Defaults of the generated master file, Gandi uses the same TTL for new
+// records. The SOA timers are the ones Gandi serves.
+const (
+	zoneFileTTL     = 10800
+	zoneFileSOATime = "10800 3600 604800 10800"
+	// zoneFileNameserver is the SOA primary when the apex has no NS record
+	zoneFileNameserver = "a.dns.gandi.net."
+)
+
+// RenderZoneFile writes the records of a zone version as an RFC 1035 master
+// file. Record names are kept relative to origin, which becomes $ORIGIN.
+// Gandi manages the SOA and does not list it, so unless the records hold one
+// it is written with the first apex nameserver as primary and the zone
+// version as serial, which BIND and other servers require to load the file.
+func RenderZoneFile(origin string, version int64, records []*record.RecordInfo) string {
+	var buf bytes.Buffer
+
+	origin = strings.TrimSuffix(origin, ".") + "."
+	fmt.Fprintf(&buf, "$ORIGIN %s\n", origin)
+	fmt.Fprintf(&buf, "$TTL %d\n", zoneFileTTL)
+
+	sorted := make([]*record.RecordInfo, len(records))
+	copy(sorted, records)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Name != sorted[j].Name {
+			return zoneFileName(sorted[i].Name) < zoneFileName(sorted[j].Name)
+		}
+		if sorted[i].Type != sorted[j].Type {
+			return sorted[i].Type < sorted[j].Type
+		}
+		return sorted[i].Value < sorted[j].Value
+	})
+
+	if !hasApexRecord(sorted, "SOA") {
+		fmt.Fprintf(&buf, "@\t%d\tIN\tSOA\t%s hostmaster.%s %d %s\n",
+			zoneFileTTL, zoneFilePrimary(sorted, origin), origin, version, zoneFileSOATime)
+	}
+
+	for _, r := range sorted {
+		fmt.Fprintf(&buf, "%s\t%d\tIN\t%s\t%s\n",
+			zoneFileName(r.Name), r.Ttl, r.Type, zoneFileValue(r.Type, r.Value))
+	}
+
+	return buf.String()
+}
+
+// hasApexRecord reports whether a record of the type is set on the zone apex
+func hasApexRecord(records []*record.RecordInfo, recordType string) bool {
+	for _, r := range records {
+		if isZoneApex(r.Name) && strings.EqualFold(r.Type, recordType) {
+			return true
+		}
+	}
+	return false
+}
+
+// zoneFilePrimary returns the first apex nameserver of the sorted records
+// as an absolute name
+func zoneFilePrimary(sorted []*record.RecordInfo, origin string) string {
+	for _, r := range sorted {
+		if isZoneApex(r.Name) && strings.EqualFold(r.Type, "NS") {
+			return recordFQDN(unquoteRecordValue(r.Value), origin)
+		}
+	}
+	return zoneFileNameserver
+}
+
+// zoneFileName returns the owner name as written in the master file
+func zoneFileName(name string) string {
+	if name == "" {
+		return "@"
+	}
+	return name
+}
+
+// zoneFileValue returns the RDATA of a record as written in the master file
+func zoneFileValue(recordType string, value string) string {
+	switch strings.ToUpper(recordType) {
+	case "TXT", "SPF":
+		var quoted []string
+		for _, s := range txtStrings(value) {
+			quoted = append(quoted, quoteCharacterStrings(s)...)
+		}
+		return strings.Join(quoted, " ")
+	default:
+		// Gandi quotes some values, SRV ones among them
+		return unquoteRecordValue(value)
+	}
+}
+
+// txtStrings decodes a TXT value into its character strings. Gandi returns
+// TXT values either bare or already quoted, possibly as several strings.
+func txtStrings(value string) []string {
+	if !strings.HasPrefix(value, `"`) {
+		return []string{value}
+	}
+
+	var result []string
+	rest := value
+	for rest != "" {
+		if rest[0] != '"' {
+			// Not a sequence of quoted strings, keep the value as a whole
+			return []string{value}
+		}
+
+		var s []byte
+		closed := false
+		i := 1
+		for ; i < len(rest); i++ {
+			c := rest[i]
+			if c == '\\' && i+1 < len(rest) {
+				if d, n := decodeEscape(rest[i+1:]); n > 0 {
+					s = append(s, d)
+					i += n
+					continue
+				}
+			}
+			if c == '"' {
+				closed = true
+				break
+			}
+			s = append(s, c)
+		}
+		if !closed {
+			return []string{value}
+		}
+
+		result = append(result, string(s))
+		rest = strings.TrimLeft(rest[i+1:], " \t")
+	}
+
+	return result
+}
+
+// decodeEscape decodes the escape following a backslash, either \DDD or \X,
+// and returns the byte with the number of characters consumed
+func decodeEscape(s string) (byte, int) {
+	if len(s) >= 3 && isDigit(s[0]) && isDigit(s[1]) && isDigit(s[2]) {
+		n := int(s[0]-'0')*100 + int(s[1]-'0')*10 + int(s[2]-'0')
+		if n <= 255 {
+			return byte(n), 3
+		}
+	}
+	if len(s) >= 1 {
+		return s[0], 1
+	}
+	return 0, 0
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// quoteCharacterStrings quotes s for a master file, escaping quotes,
+// backslashes and non-printable bytes, and splits it into 255 byte strings
+func quoteCharacterStrings(s string) []string {
+	var result []string
+	for {
+		chunk := s
+		if len(chunk) > maxCharacterString {
+			chunk = chunk[:maxCharacterString]
+		}
+		s = s[len(chunk):]
+
+		var buf bytes.Buffer
+		buf.WriteByte('"')
+		for i := 0; i < len(chunk); i++ {
+			c := chunk[i]
+			switch {
+			case c == '"' || c == '\\':
+				buf.WriteByte('\\')
+				buf.WriteByte(c)
+			case c < ' ' || c > '~':
+				fmt.Fprintf(&buf, "\\%03d", c)
+			default:
+				buf.WriteByte(c)
+			}
+		}
+		buf.WriteByte('"')
+		result = append(result, buf.String())
+
+		if s == "" {
+			return result
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bemehow/terraform-provider-gandi/gandi/domain/zone/record"
+	"github.com/miekg/dns"
+)
+
+func TestRenderZoneFile(t *testing.T) {
+	records := []*record.RecordInfo{
+		{Name: "www", Type: "CNAME", Value: "@", Ttl: 10800},
+		{Name: "@", Type: "A", Value: "1.1.1.1", Ttl: 3600},
+		{Name: "@", Type: "MX", Value: "10 relay.mail.mx.", Ttl: 3600},
+		{Name: "@", Type: "NS", Value: "b.dns.gandi.net.", Ttl: 10800},
+		{Name: "txt", Type: "TXT", Value: `"v=spf1 include:_mailcust.gandi.net ?all"`, Ttl: 300},
+		{Name: "bare", Type: "TXT", Value: `say "hi" \o/`, Ttl: 300},
+		{Name: "_sip._tcp", Type: "SRV", Value: `"10 20 5060 sip.example.com."`, Ttl: 300},
+	}
+
+	expected := strings.Join([]string{
+		"$ORIGIN example.com.",
+		"$TTL 10800",
+		"@\t10800\tIN\tSOA\tb.dns.gandi.net. hostmaster.example.com. 4 10800 3600 604800 10800",
+		"@\t3600\tIN\tA\t1.1.1.1",
+		"@\t3600\tIN\tMX\t10 relay.mail.mx.",
+		"@\t10800\tIN\tNS\tb.dns.gandi.net.",
+		"_sip._tcp\t300\tIN\tSRV\t10 20 5060 sip.example.com.",
+		"bare\t300\tIN\tTXT\t\"say \\\"hi\\\" \\\\o/\"",
+		"txt\t300\tIN\tTXT\t\"v=spf1 include:_mailcust.gandi.net ?all\"",
+		"www\t10800\tIN\tCNAME\t@",
+		"",
+	}, "\n")
+
+	if content := RenderZoneFile("example.com", 4, records); content != expected {
+		t.Fatalf("unexpected zone file:\n%s\nwant:\n%s", content, expected)
+	}
+}
+
+func TestRenderZoneFileWithoutNameservers(t *testing.T) {
+	content := RenderZoneFile("example.com.", 1, nil)
+	if !strings.Contains(content, "@\t10800\tIN\tSOA\ta.dns.gandi.net. hostmaster.example.com. 1 ") {
+		t.Fatalf("expected a default SOA, got:\n%s", content)
+	}
+
+	soa := []*record.RecordInfo{{Name: "@", Type: "SOA", Value: "ns1.example.com. admin.example.com. 7 1 1 1 1", Ttl: 300}}
+	if content := RenderZoneFile("example.com", 1, soa); strings.Count(content, "SOA") != 1 {
+		t.Fatalf("expected the SOA of the zone only, got:\n%s", content)
+	}
+}
+
+// TestZoneFileLoads parses the rendered file with a master file parser and
+// checks every record reads back with the value Gandi holds
+func TestZoneFileLoads(t *testing.T) {
+	records := []*record.RecordInfo{
+		{Name: "@", Type: "A", Value: "1.1.1.1", Ttl: 3600},
+		{Name: "@", Type: "AAAA", Value: "2001:db8::1", Ttl: 3600},
+		{Name: "@", Type: "MX", Value: "10 relay.mail.mx.", Ttl: 3600},
+		{Name: "www", Type: "CNAME", Value: "webredir.vip.gandi.net.", Ttl: 10800},
+		{Name: "_sip._tcp", Type: "SRV", Value: `"10 20 5060 sip.example.com."`, Ttl: 300},
+		{Name: "quoted", Type: "TXT", Value: `"v=spf1 include:_mailcust.gandi.net ?all"`, Ttl: 300},
+		{Name: "split", Type: "TXT", Value: `"first" "second"`, Ttl: 300},
+		{Name: "bare", Type: "TXT", Value: `say "hi" \o/`, Ttl: 300},
+		{Name: "tab", Type: "TXT", Value: "tab\there", Ttl: 300},
+		{Name: "long", Type: "TXT", Value: strings.Repeat("a", 300), Ttl: 300},
+		{Name: "spf", Type: "SPF", Value: `"v=spf1 -all"`, Ttl: 300},
+	}
+
+	content := RenderZoneFile("example.com", 2, records)
+	parser := dns.NewZoneParser(strings.NewReader(content), "", "")
+
+	var parsed []dns.RR
+	for rr, ok := parser.Next(); ok; rr, ok = parser.Next() {
+		parsed = append(parsed, rr)
+	}
+	if err := parser.Err(); err != nil {
+		t.Fatalf("zone file does not load: %s\n%s", err, content)
+	}
+	if len(parsed) != len(records)+1 || parsed[0].Header().Rrtype != dns.TypeSOA {
+		t.Fatalf("expected an SOA and %d records, got: %v", len(records), parsed)
+	}
+
+	for _, r := range records {
+		if !loadedRecord(parsed, r) {
+			t.Fatalf("record %s %s %s does not read back from:\n%s", r.Name, r.Type, r.Value, content)
+		}
+	}
+}
+
+// loadedRecord reports whether a parsed record matches r as held by Gandi
+func loadedRecord(parsed []dns.RR, r *record.RecordInfo) bool {
+	name := recordFQDN(r.Name, "example.com")
+	for _, rr := range parsed {
+		h := rr.Header()
+		if h.Name != name || dns.TypeToString[h.Rrtype] != r.Type || int64(h.Ttl) != r.Ttl {
+			continue
+		}
+
+		value := strings.TrimPrefix(rr.String(), h.String())
+		switch rr.(type) {
+		case *dns.TXT, *dns.SPF:
+			value = wireCharacterStrings(rr)
+		}
+
+		expected := unquoteRecordValue(r.Value)
+		if r.Type == "TXT" || r.Type == "SPF" {
+			expected = strings.Join(txtStrings(r.Value), "")
+		}
+		if value == expected {
+			return true
+		}
+	}
+	return false
+}
+
+func TestTxtStrings(t *testing.T) {
+	cases := map[string][]string{
+		"foo":             []string{"foo"},
+		`"foo"`:           []string{"foo"},
+		`"foo" "bar"`:     []string{"foo", "bar"},
+		`"a\"b" "c\092d"`: []string{`a"b`, `c\d`},
+		`"unterminated`:   []string{`"unterminated`},
+		`"foo" bar`:       []string{`"foo" bar`},
+	}
+
+	for value, expected := range cases {
+		if got := txtStrings(value); !reflect.DeepEqual(got, expected) {
+			t.Fatalf("txtStrings(%q) = %q, want %q", value, got, expected)
+		}
+	}
+}
+
+// wireCharacterStrings returns the character strings of a TXT or SPF record
+// as sent on the wire, joined together
+func wireCharacterStrings(rr dns.RR) string {
+	buf := make([]byte, dns.Len(rr))
+	end, err := dns.PackRR(rr, buf, 0, nil, false)
+	if err != nil {
+		return ""
+	}
+
+	var value []byte
+	for rdata := buf[end-int(rr.Header().Rdlength) : end]; len(rdata) > 0; {
+		n := int(rdata[0])
+		value = append(value, rdata[1:1+n]...)
+		rdata = rdata[1+n:]
+	}
+	return string(value)
+}